@@ -0,0 +1,344 @@
+// This file implements typed positional arguments, bound in order through a Decoder.
+
+package flag
+
+import "fmt"
+
+// argument is the interface all declared positional arguments must implement.
+type argument interface {
+	sink
+
+	// docline returns the documentation line of the argument.
+	docline() string
+
+	// synopsis returns the representation of the argument in the usage line, e.g. `[DST]`.
+	synopsis() string
+
+	// full returns true when the argument cannot consume more values.
+	full() bool
+
+	// isOptional returns true when the argument can be omitted.
+	isOptional() bool
+
+	// reset forgets the values consumed during a previous parse.
+	reset()
+
+	// finalize enforces the default value and returns an error if values are missing.
+	finalize() error
+}
+
+// FluentArg is the interface that is used for additional configuration of positional arguments.
+type FluentArg[T any] interface {
+	// Optional allows the argument to be omitted, it must not be followed by required arguments.
+	Optional() FluentArg[T]
+
+	// Default sets the value assigned when the argument is omitted, making it optional.
+	Default(T) FluentArg[T]
+
+	// Validate adds a validator that is called on every consumed value and on the default value.
+	// The validators of variadic arguments are called once on the complete slice.
+	Validate(func(T) error) FluentArg[T]
+}
+
+/////////////
+// argBase //
+/////////////
+
+type argBase[T any] struct {
+	name       string
+	docLine    string
+	dest       *T
+	def        T
+	hasDefault bool
+	optional   bool
+	validators []func(T) error
+	count      int // Number of values consumed during the current parse.
+}
+
+////////////////////////////////////////
+// FluentArg interface implementation //
+
+func (ab *argBase[T]) Optional() FluentArg[T] {
+	ab.optional = true
+	return ab
+}
+
+func (ab *argBase[T]) Default(value T) FluentArg[T] {
+	ab.def = value
+	ab.hasDefault = true
+	ab.optional = true
+	return ab
+}
+
+func (ab *argBase[T]) Validate(validator func(T) error) FluentArg[T] {
+	ab.validators = append(ab.validators, validator)
+	return ab
+}
+
+///////////////////////////////////////////////
+// Part of argument interface implementation //
+
+func (ab *argBase[T]) names() []string {
+	return []string{ab.name}
+}
+func (ab *argBase[T]) docline() string {
+	return ab.docLine
+}
+func (*argBase[T]) kind() string {
+	return "positional argument"
+}
+func (ab *argBase[T]) reset() {
+	ab.count = 0
+}
+
+func (ab *argBase[T]) validate(value T) error {
+	for _, validator := range ab.validators {
+		if err := validator(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// enforceDefault assigns the default value if no value has been consumed.
+func (ab *argBase[T]) enforceDefault() error {
+	if ab.count > 0 || !ab.hasDefault {
+		return nil
+	}
+
+	if err := ab.validate(ab.def); err != nil {
+		return fmt.Errorf("when enforcing the default of %s: %w", ab.name, err)
+	}
+
+	*ab.dest = ab.def
+	return nil
+}
+
+///////////////
+// singleArg //
+///////////////
+
+// singleArg represents a positional argument consuming exactly one value.
+type singleArg[T any, D Decoder[T]] struct {
+	argBase[T]
+	decoder D
+}
+
+func (*singleArg[T, D]) arity() int {
+	return 1
+}
+
+func (sa *singleArg[T, D]) consume(value string) error {
+	decoded, err := sa.decoder.Decode(value)
+	if err != nil {
+		return err
+	}
+
+	if err := sa.validate(decoded); err != nil {
+		return err
+	}
+
+	*sa.dest = decoded
+	sa.count++
+
+	return nil
+}
+
+func (sa *singleArg[T, D]) synopsis() string {
+	if sa.isOptional() {
+		return "[" + sa.name + "]"
+	}
+
+	return sa.name
+}
+
+func (sa *singleArg[T, D]) full() bool {
+	return sa.count > 0
+}
+
+func (sa *singleArg[T, D]) isOptional() bool {
+	return sa.optional
+}
+
+func (sa *singleArg[T, D]) finalize() error {
+	if sa.count == 0 && !sa.optional {
+		return fmt.Errorf("missing argument %s", sa.name)
+	}
+
+	return sa.enforceDefault()
+}
+
+/////////////////
+// variadicArg //
+/////////////////
+
+// variadicArg represents a positional argument consuming multiple values.
+type variadicArg[T any, D Decoder[T]] struct {
+	argBase[[]T]
+	decoder          D
+	minimum, maximum int
+}
+
+func (*variadicArg[T, D]) arity() int {
+	return -1
+}
+
+func (va *variadicArg[T, D]) consume(value string) error {
+	decoded, err := va.decoder.Decode(value)
+	if err != nil {
+		return err
+	}
+
+	// Values consumed during this parse replace the previous ones.
+	var values []T
+	if va.count > 0 {
+		values = *va.dest
+	}
+
+	// The complete slice is validated by finalize.
+	*va.dest = append(values, decoded)
+	va.count++
+
+	return nil
+}
+
+func (va *variadicArg[T, D]) synopsis() string {
+	if va.isOptional() {
+		return "[" + va.name + "...]"
+	}
+
+	return va.name + "..."
+}
+
+func (va *variadicArg[T, D]) full() bool {
+	return va.maximum > 0 && va.count >= va.maximum
+}
+
+func (va *variadicArg[T, D]) isOptional() bool {
+	return va.optional || va.minimum == 0
+}
+
+func (va *variadicArg[T, D]) finalize() error {
+	if va.count == 0 && va.optional {
+		return va.enforceDefault()
+	}
+
+	if va.count < va.minimum {
+		if va.count == 0 {
+			return fmt.Errorf("missing argument %s", va.name)
+		}
+
+		return fmt.Errorf("argument %s requires at least %d values, got %d",
+			va.name, va.minimum, va.count)
+	}
+
+	if va.count == 0 {
+		return nil
+	}
+
+	if err := va.validate(*va.dest); err != nil {
+		return consumeError(va, err)
+	}
+
+	return nil
+}
+
+//////////////////
+// Declarations //
+
+// Arg declares a positional argument consuming exactly one value.
+// Positional arguments are bound in declaration order.
+func Arg[D Decoder[T], T any](par *Parser, name string, dest *T, docline string) FluentArg[T] {
+	arg := &singleArg[T, D]{argBase: argBase[T]{name: name, docLine: docline, dest: dest}}
+	par.registerArg(arg)
+	return arg
+}
+
+// Args declares a positional argument consuming between minimum and maximum values, maximum
+// being unlimited when it is 0.
+// It must be the last positional argument.
+func Args[D Decoder[T], T any](
+	par *Parser, name string, dest *[]T, minimum, maximum int, docline string,
+) FluentArg[[]T] {
+	arg := &variadicArg[T, D]{
+		argBase: argBase[[]T]{name: name, docLine: docline, dest: dest},
+		minimum: minimum,
+		maximum: maximum,
+	}
+
+	if maximum > 0 && maximum < minimum {
+		par.errdef(fmt.Errorf("argument %s has a maximum lower than its minimum", name))
+	}
+
+	par.registerArg(arg)
+	return arg
+}
+
+///////////////
+// Utilities //
+
+func (par *Parser) registerArg(arg argument) {
+	if arg.names()[0] == "" {
+		par.errdef(fmt.Errorf("positional argument has an empty name"))
+	}
+
+	if len(par.arguments) > 0 {
+		if last := par.arguments[len(par.arguments)-1]; last.arity() < 0 {
+			par.errdef(fmt.Errorf("argument %s follows variadic argument %s",
+				arg.names()[0], last.names()[0]))
+		}
+	}
+
+	par.arguments = append(par.arguments, arg)
+}
+
+// prepareArguments resets the positional arguments and checks that no required argument follows
+// an optional one.
+// It is done at parse time because arguments can be made optional after their declaration.
+func (par *Parser) prepareArguments() error {
+	optional := ""
+	for _, arg := range par.arguments {
+		arg.reset()
+
+		if !arg.isOptional() && optional != "" {
+			return fmt.Errorf("required argument %s follows optional argument %s",
+				arg.names()[0], optional)
+		}
+
+		if arg.isOptional() && optional == "" {
+			optional = arg.names()[0]
+		}
+	}
+
+	return nil
+}
+
+// consumePositional feeds a positional value to the first declared argument that can take it,
+// or to Positional when no argument is declared.
+func (par *Parser) consumePositional(value string) error {
+	if len(par.arguments) == 0 {
+		return par.Positional.consume(value)
+	}
+
+	for _, arg := range par.arguments {
+		if !arg.full() {
+			return consumeArg(arg, value)
+		}
+	}
+
+	return fmt.Errorf("unexpected argument %q", value)
+}
+
+// finalizeArguments enforces the defaults of the positional arguments and reports the missing
+// ones.
+func (par *Parser) finalizeArguments() []error {
+	var errs []error
+	for _, arg := range par.arguments {
+		if err := arg.finalize(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
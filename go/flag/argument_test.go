@@ -0,0 +1,166 @@
+package flag
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParser_Arguments(t *testing.T) {
+	setup := func() (*Parser, *string, *string, *[]int) {
+		var (
+			src, dst string
+			sizes    []int
+		)
+
+		par := NewParser()
+		Arg[String](par, "SRC", &src, "source file")
+		Arg[String](par, "DST", &dst, "destination file").Default("out")
+		Args[Int](par, "SIZES", &sizes, 0, 2, "block sizes")
+		return par, &src, &dst, &sizes
+	}
+
+	t.Run("binding", func(t *testing.T) {
+		par, src, dst, sizes := setup()
+		noErr(t, par.Parse([]string{"in", "here", "1", "2"}))
+		eq(t, "in", *src)
+		eq(t, "here", *dst)
+		eq(t, []int{1, 2}, *sizes)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		par, src, dst, sizes := setup()
+		noErr(t, par.Parse([]string{"in"}))
+		eq(t, "in", *src)
+		eq(t, "out", *dst)
+		eq(t, []int(nil), *sizes)
+	})
+
+	t.Run("interleaved flags", func(t *testing.T) {
+		par, src, dst, _ := setup()
+		var verbose bool
+		par.Bool("verbose", &verbose, "verbose output")
+		noErr(t, par.Parse([]string{"in", "--verbose", "here"}))
+		eq(t, "in", *src)
+		eq(t, "here", *dst)
+		eq(t, true, verbose)
+	})
+
+	t.Run("missing argument", func(t *testing.T) {
+		par, _, _, _ := setup()
+		err := par.Parse(nil)
+		yesErr(t, err)
+		eq(t, "missing argument SRC", err.Error())
+	})
+
+	t.Run("unexpected argument", func(t *testing.T) {
+		par, _, _, _ := setup()
+		err := par.Parse([]string{"in", "here", "1", "2", "3"})
+		yesErr(t, err)
+		eq(t, `unexpected argument "3"`, err.Error())
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		par, _, _, _ := setup()
+		yesErr(t, par.Parse([]string{"in", "here", "one"}))
+	})
+
+	t.Run("reset across parses", func(t *testing.T) {
+		par, src, _, sizes := setup()
+		noErr(t, par.Parse([]string{"in", "here", "1"}))
+		noErr(t, par.Parse([]string{"again", "there", "2"}))
+		eq(t, "again", *src)
+		eq(t, []int{2}, *sizes)
+	})
+
+	t.Run("minimum count", func(t *testing.T) {
+		var files []string
+		par := NewParser()
+		Args[String](par, "FILES", &files, 2, 0, "input files")
+		yesErr(t, par.Parse([]string{"a"}))
+		noErr(t, par.Parse([]string{"a", "b", "c"}))
+		eq(t, []string{"a", "b", "c"}, files)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		var port int
+		par := NewParser()
+		Arg[Int](par, "PORT", &port, "port").Validate(Range(1, 65535))
+		yesErr(t, par.Parse([]string{"0"}))
+		noErr(t, par.Parse([]string{"80"}))
+		eq(t, 80, port)
+	})
+
+	t.Run("variadic validation", func(t *testing.T) {
+		var files []string
+		par := NewParser()
+		Args[String](par, "FILES", &files, 0, 0, "input files").Validate(MinItems[[]string](2))
+		err := par.Parse([]string{"a"})
+		yesErr(t, err)
+		if err != nil {
+			eq(t, "when consuming FILES (positional argument): length 1 is less than 2",
+				err.Error())
+		}
+
+		noErr(t, par.Parse([]string{"a", "b"}))
+		eq(t, []string{"a", "b"}, files)
+	})
+
+	t.Run("positional fallback", func(t *testing.T) {
+		par := NewParser()
+		noErr(t, par.Parse([]string{"a", "b"}))
+		eq(t, PositionalArguments{"a", "b"}, par.Positional)
+	})
+}
+
+func TestParser_ArgumentDefinitionErrors(t *testing.T) {
+	t.Run("argument after variadic", func(t *testing.T) {
+		par := NewParser()
+		Args[String](par, "FILES", new([]string), 0, 0, "input files")
+		Arg[String](par, "DST", new(string), "destination")
+		yesErr(t, par.Parse(nil))
+	})
+
+	t.Run("required after optional", func(t *testing.T) {
+		par := NewParser()
+		Arg[String](par, "SRC", new(string), "source").Optional()
+		Arg[String](par, "DST", new(string), "destination")
+		err := par.Parse([]string{"a", "b"})
+		yesErr(t, err)
+		eq(t, true, strings.Contains(err.Error(), "DST follows optional argument SRC"))
+	})
+
+	t.Run("maximum lower than minimum", func(t *testing.T) {
+		par := NewParser()
+		Args[String](par, "FILES", new([]string), 3, 1, "input files")
+		yesErr(t, par.Parse(nil))
+	})
+
+	t.Run("invalid default", func(t *testing.T) {
+		par := NewParser()
+		Arg[Int](par, "N", new(int), "count").Default(0).Validate(func(n int) error {
+			if n == 0 {
+				return errors.New("zero")
+			}
+			return nil
+		})
+		yesErr(t, par.Parse(nil))
+	})
+}
+
+func TestParser_ArgumentsHelp(t *testing.T) {
+	par := NewParser(WithHelp("cp", "[FLAGS]"))
+	Arg[String](par, "SRC", new(string), "source file")
+	Arg[String](par, "DST", new(string), "destination file").Optional()
+	Args[String](par, "EXTRA", new([]string), 0, 0, "extra files")
+	eq(t, `Usage: cp [FLAGS] SRC [DST] [EXTRA...]
+
+Arguments:
+  SRC    source file
+  DST    destination file
+  EXTRA  extra files
+
+Flags:
+  --help, -h  Print this help page
+`, par.Help())
+}
@@ -0,0 +1,272 @@
+// This file implements the registration of flags from the fields of a struct, described by tags:
+//   - flag: the name of the flag followed by its aliases, e.g. `flag:"port,p"`, `-` to skip the
+//     field. It defaults to the kebab-case name of the field.
+//   - default: the default value, decoded like a flag value. The values of slices and the
+//     `key=value` pairs of maps are separated by commas.
+//   - env: the environment variable bound to the flag.
+//   - help: the documentation line of the flag.
+//
+// Nested structs are bound recursively, the names of their flags being prefixed by the name of
+// the field, e.g. `--db-host`. Embedded structs are bound without prefix.
+
+package flag
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Bind registers a flag for every exported field of the struct pointed to by cfg.
+// The decoder of a field is chosen according to its type, see BindDecoder to support other types.
+// Slices and maps with string keys of supported types are bound to slice and map flags.
+// Invalid tags and unsupported types are reported as definition errors when parsing.
+func Bind(par *Parser, cfg any) {
+	value := reflect.ValueOf(cfg)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		par.errdef(fmt.Errorf("cannot bind %T, expected a pointer to a struct", cfg))
+		return
+	}
+
+	par.bindStruct(value.Elem(), "")
+}
+
+// BindDecoder makes Bind use the given decoder for the fields of type T of the parser and its
+// subcommands, overriding the default decoder of T if any.
+func BindDecoder[D Decoder[T], T any](par *Parser, decoder D) {
+	if par.binders == nil {
+		par.binders = map[reflect.Type]binder{}
+	}
+
+	par.binders[reflect.TypeFor[T]()] = newBinder(decoder)
+}
+
+/////////////
+// Binders //
+/////////////
+
+// bindSpec is the description of a flag given by the tags of a field.
+type bindSpec struct {
+	name, docline, env string
+	aliases            []string
+	def                string
+	hasDefault         bool
+}
+
+// bindFunc registers a flag storing its values in dest, which is a pointer.
+type bindFunc func(par *Parser, dest any, spec bindSpec) error
+
+// binder registers the flags of the fields of a given type, or of slices or maps of this type.
+type binder struct {
+	single, slice, stringMap bindFunc
+}
+
+// newBinder returns the binder of the fields of type T.
+func newBinder[D Decoder[T], T any](decoder D) binder {
+	return binder{
+		single: func(par *Parser, dest any, spec bindSpec) error {
+			flg := RegisterWith(par, decoder, spec.name, dest.(*T), spec.docline)
+			return configure(flg, spec, decoder.Decode)
+		},
+		slice: func(par *Parser, dest any, spec bindSpec) error {
+			flg := RegisterSliceWith(par, decoder, spec.name, dest.(*[]T), spec.docline)
+			return configure(flg, spec, func(source string) ([]T, error) {
+				var res []T
+				for _, item := range strings.Split(source, ",") {
+					decoded, err := decoder.Decode(item)
+					if err != nil {
+						return nil, err
+					}
+
+					res = append(res, decoded)
+				}
+
+				return res, nil
+			})
+		},
+		stringMap: func(par *Parser, dest any, spec bindSpec) error {
+			flg := RegisterMapWith(
+				par, String{}, decoder, spec.name, dest.(*map[string]T), spec.docline, MapOptions{})
+			return configure(flg, spec, func(source string) (map[string]T, error) {
+				res := map[string]T{}
+				for _, pair := range strings.Split(source, ",") {
+					key, value, found := strings.Cut(pair, "=")
+					if !found {
+						return nil, fmt.Errorf("expected key=value, got %q", pair)
+					}
+
+					decoded, err := decoder.Decode(value)
+					if err != nil {
+						return nil, err
+					}
+
+					res[key] = decoded
+				}
+
+				return res, nil
+			})
+		},
+	}
+}
+
+// configure applies the aliases, environment variable and default value of a spec to a flag.
+func configure[T any](flg FluentFlag[T], spec bindSpec, decode func(string) (T, error)) error {
+	flg.Alias(spec.aliases...)
+	if spec.env != "" {
+		flg.Env(spec.env)
+	}
+
+	if !spec.hasDefault {
+		return nil
+	}
+
+	def, err := decode(spec.def)
+	if err != nil {
+		return fmt.Errorf("invalid default %q: %w", spec.def, err)
+	}
+
+	flg.Default(def)
+	return nil
+}
+
+// defaultBinders are the binders of the types supported out of the box.
+var defaultBinders = map[reflect.Type]binder{
+	reflect.TypeFor[int]():              newBinder(Int{}),
+	reflect.TypeFor[int8]():             newBinder(Int8{}),
+	reflect.TypeFor[int16]():            newBinder(Int16{}),
+	reflect.TypeFor[int32]():            newBinder(Int32{}),
+	reflect.TypeFor[int64]():            newBinder(Int64{}),
+	reflect.TypeFor[uint]():             newBinder(Uint{}),
+	reflect.TypeFor[uint8]():            newBinder(Uint8{}),
+	reflect.TypeFor[uint16]():           newBinder(Uint16{}),
+	reflect.TypeFor[uint32]():           newBinder(Uint32{}),
+	reflect.TypeFor[uint64]():           newBinder(Uint64{}),
+	reflect.TypeFor[float32]():          newBinder(Float32{}),
+	reflect.TypeFor[float64]():          newBinder(Float64{}),
+	reflect.TypeFor[string]():           newBinder(String{}),
+	reflect.TypeFor[bool]():             newBinder(Bool{}),
+	reflect.TypeFor[time.Duration]():    newBinder(Duration{}),
+	reflect.TypeFor[time.Time]():        newBinder(Time{}),
+	reflect.TypeFor[*url.URL]():         newBinder(URL{}),
+	reflect.TypeFor[netip.AddrPort]():   newBinder(AddrPort{}),
+	reflect.TypeFor[netip.Addr]():       newBinder(Addr{}),
+	reflect.TypeFor[netip.Prefix]():     newBinder(Prefix{}),
+	reflect.TypeFor[net.HardwareAddr](): newBinder(MAC{}),
+}
+
+// binder returns the binder of a type, looking into the parser and its ancestors before the
+// default binders.
+func (par *Parser) binder(typ reflect.Type) (binder, bool) {
+	for cur := par; cur != nil; cur = cur.parent {
+		if res, exists := cur.binders[typ]; exists {
+			return res, true
+		}
+	}
+
+	res, exists := defaultBinders[typ]
+	return res, exists
+}
+
+/////////////
+// Binding //
+/////////////
+
+// bindStruct registers the flags of the fields of a struct, prefixing their names.
+func (par *Parser) bindStruct(value reflect.Value, prefix string) {
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+		tag, tagged := field.Tag.Lookup("flag")
+		if tag == "-" {
+			continue
+		}
+
+		// The exported fields of embedded structs are accessible even if their type is unexported.
+		embedded := field.Anonymous && field.Type.Kind() == reflect.Struct
+		if !field.IsExported() && !embedded {
+			if tagged {
+				par.errdef(fmt.Errorf("cannot bind unexported field %s", field.Name))
+			}
+
+			continue
+		}
+
+		names := strings.Split(tag, ",")
+		if names[0] == "" {
+			names[0] = kebab(field.Name)
+		}
+
+		spec := bindSpec{
+			name:    prefix + names[0],
+			aliases: names[1:],
+			docline: field.Tag.Get("help"),
+			env:     field.Tag.Get("env"),
+		}
+		spec.def, spec.hasDefault = field.Tag.Lookup("default")
+
+		if err := par.bindField(value.Field(i), field, prefix, spec); err != nil {
+			par.errdef(fmt.Errorf("when binding field %s: %w", field.Name, err))
+		}
+	}
+}
+
+// bindField registers the flag of a field, or the flags of its fields if it is a struct without
+// binder.
+func (par *Parser) bindField(
+	value reflect.Value, field reflect.StructField, prefix string, spec bindSpec,
+) error {
+	typ := field.Type
+	if bnd, exists := par.binder(typ); exists {
+		return bnd.single(par, value.Addr().Interface(), spec)
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		if field.Anonymous {
+			par.bindStruct(value, prefix)
+		} else {
+			par.bindStruct(value, spec.name+"-")
+		}
+
+		return nil
+
+	case reflect.Slice:
+		if bnd, exists := par.binder(typ.Elem()); exists {
+			return bnd.slice(par, value.Addr().Interface(), spec)
+		}
+
+	case reflect.Map:
+		if typ.Key() != reflect.TypeFor[string]() {
+			return fmt.Errorf("unsupported map key type %s, expected string", typ.Key())
+		}
+
+		if bnd, exists := par.binder(typ.Elem()); exists {
+			return bnd.stringMap(par, value.Addr().Interface(), spec)
+		}
+	}
+
+	return fmt.Errorf("unsupported type %s", typ)
+}
+
+// kebab converts a Go identifier to kebab case, e.g. `HTTPPort` to `http-port`.
+func kebab(name string) string {
+	var builder strings.Builder
+	runes := []rune(name)
+	for i, char := range runes {
+		if i > 0 && unicode.IsUpper(char) {
+			previous := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(previous) || nextLower {
+				builder.WriteRune('-')
+			}
+		}
+
+		builder.WriteRune(unicode.ToLower(char))
+	}
+
+	return builder.String()
+}
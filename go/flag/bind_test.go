@@ -0,0 +1,142 @@
+package flag
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindDB struct {
+	Host string `help:"database host" default:"localhost"`
+	Port int    `help:"database port" default:"5432" env:"DB_PORT"`
+}
+
+type bindCommon struct {
+	Verbose bool `flag:"verbose,v" help:"verbose output"`
+}
+
+type bindConfig struct {
+	bindCommon
+	Port     int               `flag:"port,p" default:"8080" env:"PORT" help:"listen port"`
+	Timeout  time.Duration     `default:"30s" help:"request timeout"`
+	MaxConns uint              `help:"maximum connections"`
+	Tags     []string          `flag:"tag" default:"a,b" help:"tags"`
+	Labels   map[string]int    `help:"labels"`
+	DB       bindDB            `flag:"db"`
+	Level    level             `default:"info" help:"log level"`
+	Skipped  string            `flag:"-"`
+	Meta     map[string]string `flag:"meta"`
+	ignored  int
+}
+
+func TestBind(t *testing.T) {
+	setup := func() (*Parser, *bindConfig) {
+		var cfg bindConfig
+		par := NewParser()
+		BindDecoder(par, Choice[level]{Values: []level{0, 1, 2}})
+		Bind(par, &cfg)
+		return par, &cfg
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		par, cfg := setup()
+		noErr(t, par.Parse(nil))
+		eq(t, 8080, cfg.Port)
+		eq(t, 30*time.Second, cfg.Timeout)
+		eq(t, []string{"a", "b"}, cfg.Tags)
+		eq(t, "localhost", cfg.DB.Host)
+		eq(t, 5432, cfg.DB.Port)
+		eq(t, level(1), cfg.Level)
+		eq(t, false, cfg.Verbose)
+	})
+
+	t.Run("arguments", func(t *testing.T) {
+		par, cfg := setup()
+		noErr(t, par.Parse([]string{
+			"-p", "9090", "-v", "--max-conns", "10", "--tag", "x", "--labels", "a=1",
+			"--db-host", "db.local", "--level", "error", "--meta", "k=v",
+		}))
+		eq(t, 9090, cfg.Port)
+		eq(t, true, cfg.Verbose)
+		eq(t, uint(10), cfg.MaxConns)
+		eq(t, []string{"x"}, cfg.Tags)
+		eq(t, map[string]int{"a": 1}, cfg.Labels)
+		eq(t, "db.local", cfg.DB.Host)
+		eq(t, level(2), cfg.Level)
+		eq(t, map[string]string{"k": "v"}, cfg.Meta)
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv("DB_PORT", "6543")
+		par, cfg := setup()
+		noErr(t, par.Parse(nil))
+		eq(t, 6543, cfg.DB.Port)
+	})
+
+	t.Run("skipped fields", func(t *testing.T) {
+		par, _ := setup()
+		yesErr(t, par.Parse([]string{"--skipped", "x"}))
+		yesErr(t, par.Parse([]string{"--ignored", "1"}))
+	})
+
+	t.Run("help", func(t *testing.T) {
+		par, _ := setup()
+		help := par.Help()
+		for _, expected := range []string{
+			"--port, -p INT",
+			"listen port (default: 8080) [env: PORT]",
+			"--db-port INT",
+			"--labels KEY=VALUE",
+		} {
+			if !strings.Contains(help, expected) {
+				t.Errorf("expected %q in:\n%s", expected, help)
+			}
+		}
+	})
+}
+
+func TestBind_Errors(t *testing.T) {
+	bindErr := func(t *testing.T, cfg any) {
+		t.Helper()
+		par := NewParser()
+		Bind(par, cfg)
+		yesErr(t, par.Parse(nil))
+	}
+
+	t.Run("not a struct pointer", func(t *testing.T) {
+		bindErr(t, bindConfig{})
+		bindErr(t, new(int))
+	})
+
+	t.Run("invalid default", func(t *testing.T) {
+		bindErr(t, &struct {
+			Port int `default:"http"`
+		}{})
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		bindErr(t, &struct{ Channel chan int }{})
+		bindErr(t, &struct{ Counts map[int]int }{})
+	})
+
+	t.Run("unexported tagged field", func(t *testing.T) {
+		bindErr(t, &struct {
+			port int `flag:"port"`
+		}{})
+	})
+
+	t.Run("conflicting names", func(t *testing.T) {
+		bindErr(t, &struct {
+			A int `flag:"name"`
+			B int `flag:"name"`
+		}{})
+	})
+}
+
+func TestKebab(t *testing.T) {
+	eq(t, "port", kebab("Port"))
+	eq(t, "max-conns", kebab("MaxConns"))
+	eq(t, "http-port", kebab("HTTPPort"))
+	eq(t, "db", kebab("DB"))
+	eq(t, "url-path", kebab("URLPath"))
+}
@@ -0,0 +1,97 @@
+// This file implements subcommands, which are parsers nested in a parent parser.
+
+package flag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Command registers a subcommand and returns its parser.
+//
+// The subcommand is selected when its name is the first positional argument, the following
+// arguments are then parsed by the returned parser, which also accepts the persistent flags of its
+// ancestors.
+// usage is the part of the usage line following the command name.
+func (par *Parser) Command(name, usage string) *Parser {
+	program := strings.TrimSpace(par.program + " " + name)
+	cmd := &Parser{
+		flags:           flagset{},
+		usage:           strings.TrimSpace(program + " " + usage),
+		help:            par.help,
+		helpWidth:       par.helpWidth,
+		envPrefix:       par.envPrefix,
+		bundling:        par.bundling,
+		passthrough:     par.passthrough,
+		negativeNumbers: par.negativeNumbers,
+		lenientConfig:   par.lenientConfig,
+		name:            name,
+		program:         program,
+		parent:          par,
+		output:          par.output,
+		exit:            par.exit,
+	}
+
+	switch {
+	case name == "" || strings.HasPrefix(name, "-"):
+		par.errdef(fmt.Errorf("invalid command name %q", name))
+	case par.command(name) != nil:
+		par.errdef(fmt.Errorf("command %s already exists", name))
+	}
+
+	par.commands = append(par.commands, cmd)
+	return cmd
+}
+
+// CommandPath returns the names of the subcommands selected during the last parse, from the
+// outermost to the innermost.
+func (par *Parser) CommandPath() []string {
+	var res []string
+	for cmd := par.selected; cmd != nil; cmd = cmd.selected {
+		res = append(res, cmd.name)
+	}
+
+	return res
+}
+
+// summary returns the usage of the command without the invocation prefix.
+func (par *Parser) summary() string {
+	return strings.TrimSpace(strings.TrimPrefix(par.usage, par.program))
+}
+
+// command returns the subcommand with the given name, or nil if it does not exist.
+func (par *Parser) command(name string) *Parser {
+	for _, cmd := range par.commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+
+	return nil
+}
+
+// leaf returns the innermost subcommand selected during the last parse.
+func (par *Parser) leaf() *Parser {
+	res := par
+	for res.selected != nil {
+		res = res.selected
+	}
+
+	return res
+}
+
+// inherited returns the persistent flags of the ancestors of the parser.
+func (par *Parser) inherited() []flag {
+	if par.parent == nil {
+		return nil
+	}
+
+	var res []flag
+	for _, flg := range par.parent.canonical {
+		if flg.persistent() {
+			res = append(res, flg)
+		}
+	}
+
+	return append(par.parent.inherited(), res...)
+}
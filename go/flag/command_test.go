@@ -0,0 +1,125 @@
+package flag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParser_Commands(t *testing.T) {
+	setup := func() (*Parser, *int, *string, *bool) {
+		var (
+			verbose int
+			url     string
+			force   bool
+		)
+
+		par := NewParser()
+		par.Int("verbose", &verbose, "verbosity").Persistent()
+		remote := par.Command("remote", "[FLAGS]")
+		add := remote.Command("add", "NAME URL")
+		add.String("url", &url, "remote url")
+		add.Bool("force", &force, "overwrite")
+		return par, &verbose, &url, &force
+	}
+
+	t.Run("nested dispatch", func(t *testing.T) {
+		par, verbose, url, force := setup()
+		noErr(t, par.Parse([]string{"remote", "add", "--url", "here", "--verbose", "2", "--force"}))
+		eq(t, []string{"remote", "add"}, par.CommandPath())
+		eq(t, 2, *verbose)
+		eq(t, "here", *url)
+		eq(t, true, *force)
+	})
+
+	t.Run("parent flags before command", func(t *testing.T) {
+		par, verbose, _, _ := setup()
+		noErr(t, par.Parse([]string{"--verbose", "3", "remote"}))
+		eq(t, []string{"remote"}, par.CommandPath())
+		eq(t, 3, *verbose)
+	})
+
+	t.Run("no command", func(t *testing.T) {
+		par, _, _, _ := setup()
+		noErr(t, par.Parse([]string{"other", "remote"}))
+		eq(t, []string(nil), par.CommandPath())
+		eq(t, []string{"other", "remote"}, []string(par.Positional))
+	})
+
+	t.Run("child flags are not visible to the parent", func(t *testing.T) {
+		par, _, _, _ := setup()
+		yesErr(t, par.Parse([]string{"--url", "here", "remote", "add"}))
+	})
+
+	t.Run("non-persistent flags are not inherited", func(t *testing.T) {
+		par := NewParser()
+		par.Int("local", new(int), "not inherited")
+		par.Command("cmd", "")
+		yesErr(t, par.Parse([]string{"cmd", "--local", "1"}))
+	})
+
+	t.Run("duplicate command", func(t *testing.T) {
+		par := NewParser()
+		par.Command("cmd", "")
+		par.Command("cmd", "")
+		yesErr(t, par.Parse(nil))
+	})
+
+	t.Run("child definition errors", func(t *testing.T) {
+		par := NewParser()
+		par.Command("cmd", "").Int("", new(int), "no name")
+		yesErr(t, par.Parse(nil))
+	})
+
+	t.Run("persistent conflict", func(t *testing.T) {
+		par := NewParser()
+		par.Int("flag", new(int), "parent").Persistent()
+		par.Command("cmd", "").Int("flag", new(int), "child")
+		yesErr(t, par.Parse([]string{"cmd"}))
+	})
+}
+
+func TestParser_CommandHelp(t *testing.T) {
+	var out strings.Builder
+	exited := false
+
+	par := NewParser(WithHelp("tool", "COMMAND"))
+	par.output = &out
+	par.exit = func(int) { exited = true }
+	par.Command("run", "[FLAGS] FILE").Int("jobs", new(int), "parallel jobs").Alias("j")
+	par.Command("stop", "")
+
+	expected := `Usage: tool run [FLAGS] FILE
+
+Flags:
+  --jobs, -j INT  parallel jobs
+
+Global flags:
+  --help, -h  Print this help page
+`
+
+	noErr(t, par.Parse([]string{"help", "run"}))
+	eq(t, true, exited)
+	eq(t, expected, out.String())
+
+	out.Reset()
+	noErr(t, par.Parse([]string{"run", "-h"}))
+	eq(t, expected, out.String())
+
+	eq(t, `Usage: tool COMMAND
+
+Flags:
+  --help, -h  Print this help page
+
+Commands:
+  run   [FLAGS] FILE
+  stop  
+`, par.Help())
+
+	eq(t, `Usage: tool stop
+
+Flags:
+
+Global flags:
+  --help, -h  Print this help page
+`, par.command("stop").Help())
+}
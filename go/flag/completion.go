@@ -0,0 +1,445 @@
+// This file generates static completion scripts for bash, zsh and fish.
+//
+// The values of the flags having a completion function are completed dynamically, the scripts
+// calling the program in completion mode (see dynamic-completion.go).
+
+package flag
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/mooss/bagend/go/fun/eager/lie"
+)
+
+// Completion writes a completion script for the given shell (bash, zsh or fish) to w.
+// The scripts complete the flags and subcommands of the program named by WithHelp, the values of
+// the flags having a completion function being obtained by calling the program.
+func (par *Parser) Completion(shell string, w io.Writer) error {
+	if par.program == "" {
+		return errors.New("completion requires a program name, see WithHelp")
+	}
+
+	var script string
+	switch shell {
+	case "bash":
+		script = par.bashCompletion()
+	case "zsh":
+		script = par.zshCompletion()
+	case "fish":
+		script = par.fishCompletion()
+	default:
+		return fmt.Errorf("unsupported shell %q, expected bash, zsh or fish", shell)
+	}
+
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+//////////
+// Bash //
+//////////
+
+func (par *Parser) bashCompletion() string {
+	var builder strings.Builder
+	function := "_" + identifier(par.program)
+	fmt.Fprintf(&builder, "# bash completion for %s\n\n", par.program)
+	if par.dynamic() {
+		fmt.Fprintf(&builder, `%s() {
+	local line
+	COMPREPLY=()
+	while IFS= read -r line; do
+		[[ $line == :* ]] || COMPREPLY+=("${line%%%%$'\t'*}")
+	done < <(%s %s "$((COMP_CWORD - 1))" "${COMP_WORDS[@]:1}" 2>/dev/null)
+}
+
+`, dynamicFunction(par.program), par.program, completeCommand)
+	}
+
+	fmt.Fprintf(&builder, "%s() {\n", function)
+	builder.WriteString(`	local cur="${COMP_WORDS[COMP_CWORD]}"
+	local prev="${COMP_WORDS[COMP_CWORD-1]}"
+	local path="" word i flags commands
+`)
+
+	tree := par.tree()
+	if len(tree) > 1 {
+		paths := lie.Map(func(cmd *Parser) string { return shellQuote(par.path(cmd)) }, tree[1:])
+		fmt.Fprintf(&builder, `	for ((i = 1; i < COMP_CWORD; i++)); do
+		word="${path:+$path }${COMP_WORDS[i]}"
+		case "$word" in
+			%s) path="$word" ;;
+		esac
+	done
+`, strings.Join(paths, "|"))
+	}
+
+	builder.WriteString("\n\tcase \"$path\" in\n")
+	for _, cmd := range tree {
+		fmt.Fprintf(&builder, "\t\t%s)\n", shellQuote(par.path(cmd)))
+
+		flags := cmd.completable()
+		valued := slices.DeleteFunc(slices.Clone(flags), func(flg flag) bool {
+			return flg.arity() == 0
+		})
+
+		if len(valued) > 0 {
+			builder.WriteString("\t\t\tcase \"$prev\" in\n")
+			for _, flg := range valued {
+				fmt.Fprintf(&builder, "\t\t\t\t%s) %s; return ;;\n",
+					strings.Join(spellings(flg), "|"), bashValues(flg, par.program))
+			}
+			builder.WriteString("\t\t\tesac\n")
+		}
+
+		names := lie.Map(func(cmd *Parser) string { return cmd.name }, cmd.commands)
+		fmt.Fprintf(&builder, "\t\t\tflags=%s\n\t\t\tcommands=%s\n\t\t\t;;\n",
+			shellQuote(strings.Join(slices.Concat(lie.Map(spellings, flags)...), " ")),
+			shellQuote(strings.Join(names, " ")))
+	}
+
+	fmt.Fprintf(&builder, `	esac
+
+	if [[ $cur == -* ]]; then
+		COMPREPLY=($(compgen -W "$flags" -- "$cur"))
+	elif [[ -n $commands ]]; then
+		COMPREPLY=($(compgen -W "$commands" -- "$cur"))
+	else
+		compopt -o filenames 2>/dev/null
+		COMPREPLY=($(compgen -f -- "$cur"))
+	fi
+}
+
+complete -F %s %s
+`, function, par.program)
+
+	return builder.String()
+}
+
+// bashValues returns the bash statement completing the value of a flag of the program.
+func bashValues(flg flag, program string) string {
+	if flg.completer() != nil {
+		return dynamicFunction(program)
+	}
+
+	if choices := flg.choices(); choices != nil {
+		return `COMPREPLY=($(compgen -W ` + shellQuote(strings.Join(choices, " ")) + ` -- "$cur"))`
+	}
+
+	switch flg.files() {
+	case anyFiles:
+		return `compopt -o filenames 2>/dev/null; COMPREPLY=($(compgen -f -- "$cur"))`
+	case dirsOnly:
+		return `compopt -o filenames 2>/dev/null; COMPREPLY=($(compgen -d -- "$cur"))`
+	default:
+		return "COMPREPLY=()"
+	}
+}
+
+/////////
+// Zsh //
+/////////
+
+func (par *Parser) zshCompletion() string {
+	var builder strings.Builder
+	function := "_" + identifier(par.program)
+	fmt.Fprintf(&builder, "#compdef %s\n", par.program)
+
+	// The words are saved before _arguments shifts them when entering a subcommand.
+	dynamic := dynamicFunction(par.program)
+	if par.dynamic() {
+		fmt.Fprintf(&builder, `
+%s() {
+	local line
+	local -a candidates args=("${(@)_completed_words[2,-1]}")
+	for line in "${(@f)$(%s %s $((_completed_current - 2)) "${args[@]}" 2>/dev/null)}"; do
+		[[ $line == :* ]] || candidates+=("${line%%%%$'\t'*}")
+	done
+	compadd -- "${candidates[@]}"
+}
+`, dynamic, par.program, completeCommand)
+	}
+
+	for _, cmd := range par.tree() {
+		fmt.Fprintf(&builder, "\n_%s() {\n\tlocal state\n", identifier(cmd.program))
+		if cmd == par && par.dynamic() {
+			builder.WriteString("\tlocal -a _completed_words=(\"${words[@]}\")\n")
+			builder.WriteString("\tlocal _completed_current=$CURRENT\n")
+		}
+
+		builder.WriteString("\t_arguments -C")
+		for _, flg := range cmd.completable() {
+			builder.WriteString(" \\\n\t\t" + zshSpec(flg, dynamic))
+		}
+
+		if len(cmd.commands) == 0 {
+			builder.WriteString(" \\\n\t\t'*: :_files'\n}\n")
+			continue
+		}
+
+		builder.WriteString(" \\\n\t\t'1: :->command' \\\n\t\t'*:: :->args'\n\n")
+		builder.WriteString("\tcase $state in\n\t\tcommand)\n\t\t\tlocal -a commands=(\n")
+		for _, sub := range cmd.commands {
+			description := strings.ReplaceAll(sub.name, ":", `\:`)
+			if summary := sub.summary(); summary != "" {
+				description += ":" + summary
+			}
+
+			builder.WriteString("\t\t\t\t" + shellQuote(description) + "\n")
+		}
+
+		builder.WriteString("\t\t\t)\n\t\t\t_describe command commands\n\t\t\t;;\n")
+		builder.WriteString("\t\targs)\n\t\t\tcase $words[1] in\n")
+		for _, sub := range cmd.commands {
+			fmt.Fprintf(&builder, "\t\t\t\t%s) _%s ;;\n",
+				shellQuote(sub.name), identifier(sub.program))
+		}
+
+		builder.WriteString("\t\t\tesac\n\t\t\t;;\n\tesac\n}\n")
+	}
+
+	fmt.Fprintf(&builder, `
+if [ "$funcstack[1]" = "%[1]s" ]; then
+	%[1]s "$@"
+else
+	compdef %[1]s %[2]s
+fi
+`, function, par.program)
+
+	return builder.String()
+}
+
+// zshSpec returns the _arguments specification of a flag, e.g.
+// `'(-f --format)'{-f,--format}'[output format]:FORMAT:(json yaml)'`.
+// The values of the flags having a completion function are completed by the dynamic function.
+func zshSpec(flg flag, dynamic string) string {
+	names := spellings(flg)
+	description := "[" + zshEscaper.Replace(flg.docline()) + "]"
+	if flg.arity() != 0 {
+		placeholder := flg.placeholder()
+		if placeholder == "" {
+			placeholder = "VALUE"
+		}
+
+		description += ":" + zshEscaper.Replace(placeholder) + ":" + zshValues(flg, dynamic)
+	}
+
+	prefix := ""
+	switch {
+	case flg.arity() < 0:
+		prefix = "*"
+	case len(names) > 1:
+		prefix = "(" + strings.Join(names, " ") + ")"
+	}
+
+	if len(names) == 1 {
+		return shellQuote(prefix + names[0] + description)
+	}
+
+	return shellQuote(prefix) + "{" + strings.Join(names, ",") + "}" + shellQuote(description)
+}
+
+// zshValues returns the _arguments action completing the value of a flag.
+func zshValues(flg flag, dynamic string) string {
+	if flg.completer() != nil {
+		return dynamic
+	}
+
+	if choices := flg.choices(); choices != nil {
+		escape := func(choice string) string {
+			return strings.ReplaceAll(zshEscaper.Replace(choice), " ", `\ `)
+		}
+
+		return "(" + strings.Join(lie.Map(escape, choices), " ") + ")"
+	}
+
+	switch flg.files() {
+	case anyFiles:
+		return "_files"
+	case dirsOnly:
+		return "_files -/"
+	default:
+		return " "
+	}
+}
+
+// zshEscaper escapes the characters that are special in the descriptions and actions of
+// _arguments.
+var zshEscaper = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, ":", `\:`)
+
+//////////
+// Fish //
+//////////
+
+func (par *Parser) fishCompletion() string {
+	var builder strings.Builder
+	function := "__" + identifier(par.program) + "_at"
+	tree := par.tree()
+	paths := lie.Map(func(cmd *Parser) string { return fishQuote(par.path(cmd)) }, tree[1:])
+
+	// The function checks the subcommands given so far, e.g. `__tool_at 'remote add'`.
+	fmt.Fprintf(&builder, `# fish completion for %s
+
+function %s
+	set -l path
+	for token in (commandline -opc)[2..-1]
+		set -l candidate (string join ' ' $path $token)
+		if contains -- $candidate %s
+			set path $candidate
+		end
+	end
+	test "$path" = "$argv"
+end
+`, par.program, function, strings.Join(paths, " "))
+
+	dynamic := dynamicFunction(par.program)
+	if par.dynamic() {
+		fmt.Fprintf(&builder, `
+function %s
+	set -l words (commandline -opc)[2..-1]
+	%s %s (count $words) $words (commandline -ct) 2>/dev/null | string match -rv '^:'
+end
+`, dynamic, par.program, completeCommand)
+	}
+
+	for _, cmd := range tree {
+		prefix := "complete -c " + par.program + " -n " +
+			fishQuote(function+" "+fishQuote(par.path(cmd)))
+		builder.WriteString("\n")
+
+		for _, sub := range cmd.commands {
+			builder.WriteString(prefix + " -f -a " + fishQuote(sub.name))
+			if summary := sub.summary(); summary != "" {
+				builder.WriteString(" -d " + fishQuote(summary))
+			}
+
+			builder.WriteString("\n")
+		}
+
+		for _, flg := range cmd.completable() {
+			builder.WriteString(prefix + fishSpec(flg, dynamic) + "\n")
+		}
+	}
+
+	return builder.String()
+}
+
+// fishSpec returns the options of the complete builtin describing a flag.
+// The values of the flags having a completion function are completed by the dynamic function.
+func fishSpec(flg flag, dynamic string) string {
+	var builder strings.Builder
+	names := flg.names()
+	if flg.negation() != "" {
+		names = append(slices.Clone(names), "no-"+names[0])
+	}
+
+	for _, name := range names {
+		if len(name) == 1 {
+			builder.WriteString(" -s " + fishQuote(name))
+		} else {
+			builder.WriteString(" -l " + fishQuote(name))
+		}
+	}
+
+	if flg.docline() != "" {
+		builder.WriteString(" -d " + fishQuote(flg.docline()))
+	}
+
+	if flg.arity() == 0 {
+		return builder.String()
+	}
+
+	switch {
+	case flg.completer() != nil:
+		builder.WriteString(" -xa " + fishQuote("("+dynamic+")"))
+	case flg.choices() != nil:
+		builder.WriteString(" -xa " + fishQuote(strings.Join(flg.choices(), " ")))
+	case flg.files() == anyFiles:
+		builder.WriteString(" -rF")
+	case flg.files() == dirsOnly:
+		builder.WriteString(" -xa '(__fish_complete_directories)'")
+	default:
+		builder.WriteString(" -x")
+	}
+
+	return builder.String()
+}
+
+///////////////
+// Utilities //
+
+// tree returns the parser and all its subcommands, depth first.
+func (par *Parser) tree() []*Parser {
+	res := []*Parser{par}
+	for _, cmd := range par.commands {
+		res = append(res, cmd.tree()...)
+	}
+
+	return res
+}
+
+// path returns the names of the subcommands leading from the parser to cmd, separated by spaces.
+func (par *Parser) path(cmd *Parser) string {
+	return strings.TrimSpace(strings.TrimPrefix(cmd.program, par.program))
+}
+
+// completable returns the flags that can be completed, i.e. the visible flags of the parser and
+// its ancestors.
+func (par *Parser) completable() []flag {
+	return append(par.shown(), par.shownInherited()...)
+}
+
+// dynamic returns true if the parser or one of its subcommands has a flag completed dynamically.
+func (par *Parser) dynamic() bool {
+	for _, cmd := range par.tree() {
+		for _, flg := range cmd.completable() {
+			if flg.completer() != nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// dynamicFunction returns the name of the shell function completing values dynamically.
+// The double underscore prevents conflicts with the functions of the subcommands.
+func dynamicFunction(program string) string {
+	return "__" + identifier(program) + "_complete"
+}
+
+// spellings returns all the ways a flag can be written on the command line.
+func spellings(flg flag) []string {
+	res := lie.Map(name2flag, flg.names())
+	if flg.negation() != "" {
+		res = append(res, "--no-"+flg.names()[0])
+	}
+
+	return res
+}
+
+// identifier replaces the characters of a program name that are not valid in a shell function
+// name.
+func identifier(program string) string {
+	return strings.Map(func(char rune) rune {
+		switch {
+		case 'a' <= char && char <= 'z', 'A' <= char && char <= 'Z', '0' <= char && char <= '9':
+			return char
+		default:
+			return '_'
+		}
+	}, program)
+}
+
+// shellQuote quotes a string for POSIX shells.
+func shellQuote(str string) string {
+	return "'" + strings.ReplaceAll(str, "'", `'\''`) + "'"
+}
+
+// fishQuote quotes a string for fish.
+func fishQuote(str string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(str) + "'"
+}
@@ -0,0 +1,116 @@
+package flag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParser_Completion(t *testing.T) {
+	setup := func() *Parser {
+		par := NewParser(WithHelp("tool", "[FLAGS]"))
+		par.Choice("format", new(string), []string{"json", "yaml"}, "output format").Alias("f")
+		par.Path("config", new(string), "config file")
+		RegisterWith(par, Path{Dir: true}, "workdir", new(string), "working directory")
+		par.StringSlice("tag", new([]string), "tags")
+		par.Bool("color", new(bool), "colored output")
+		par.Int("secret", new(int), "hidden flag").Hidden()
+		remote := par.Command("remote", "[FLAGS] NAME")
+		remote.Int("jobs", new(int), "parallel jobs").Alias("j")
+		return par
+	}
+
+	script := func(t *testing.T, shell string) string {
+		t.Helper()
+		var builder strings.Builder
+		noErr(t, setup().Completion(shell, &builder))
+		if strings.Contains(builder.String(), "secret") {
+			t.Errorf("hidden flag in %s completion", shell)
+		}
+
+		return builder.String()
+	}
+
+	contains := func(t *testing.T, script string, lines ...string) {
+		t.Helper()
+		for _, line := range lines {
+			if !strings.Contains(script, line) {
+				t.Errorf("expected line %q in:\n%s", line, script)
+			}
+		}
+	}
+
+	t.Run("bash", func(t *testing.T) {
+		contains(t, script(t, "bash"),
+			`'remote') path="$word" ;;`,
+			`--format|-f) COMPREPLY=($(compgen -W 'json yaml' -- "$cur")); return ;;`,
+			`--config) compopt -o filenames 2>/dev/null; COMPREPLY=($(compgen -f -- "$cur"))`,
+			`--workdir) compopt -o filenames 2>/dev/null; COMPREPLY=($(compgen -d -- "$cur"))`,
+			`flags='--help -h --format -f --config --workdir --tag --color --no-color'`,
+			`commands='remote'`,
+			`flags='--jobs -j --help -h'`,
+			"complete -F _tool tool",
+		)
+	})
+
+	t.Run("zsh", func(t *testing.T) {
+		contains(t, script(t, "zsh"),
+			"#compdef tool",
+			`'(--format -f)'{--format,-f}'[output format]:{json,yaml}:(json yaml)'`,
+			`'--config[config file]:PATH:_files'`,
+			`'--workdir[working directory]:DIR:_files -/'`,
+			`'*--tag[tags]:STRING...: '`,
+			`'(--color --no-color)'{--color,--no-color}'[colored output]'`,
+			`'remote:[FLAGS] NAME'`,
+			`'remote') _tool_remote ;;`,
+			"_tool_remote() {",
+		)
+	})
+
+	t.Run("fish", func(t *testing.T) {
+		contains(t, script(t, "fish"),
+			`if contains -- $candidate 'remote'`,
+			`complete -c tool -n '__tool_at \'\'' -f -a 'remote' -d '[FLAGS] NAME'`,
+			`-l 'format' -s 'f' -d 'output format' -xa 'json yaml'`,
+			`-l 'config' -d 'config file' -rF`,
+			`-l 'workdir' -d 'working directory' -xa '(__fish_complete_directories)'`,
+			`-l 'color' -l 'no-color' -d 'colored output'`,
+			`complete -c tool -n '__tool_at \'remote\'' -l 'jobs' -s 'j' -d 'parallel jobs' -x`,
+		)
+	})
+
+	t.Run("dynamic", func(t *testing.T) {
+		par := NewParser(WithHelp("tool", "[FLAGS]"))
+		par.String("branch", new(string), "branch").Complete(func(string) []string { return nil })
+		generate := func(shell string) string {
+			var builder strings.Builder
+			noErr(t, par.Completion(shell, &builder))
+			return builder.String()
+		}
+
+		contains(t, generate("bash"),
+			`done < <(tool __complete "$((COMP_CWORD - 1))" "${COMP_WORDS[@]:1}" 2>/dev/null)`,
+			`--branch) __tool_complete; return ;;`,
+		)
+		contains(t, generate("zsh"),
+			`"${(@f)$(tool __complete $((_completed_current - 2)) "${args[@]}" 2>/dev/null)}"`,
+			`local -a _completed_words=("${words[@]}")`,
+			`'--branch[branch]:STRING:__tool_complete'`,
+		)
+		contains(t, generate("fish"),
+			`tool __complete (count $words) $words (commandline -ct) 2>/dev/null`,
+			`-l 'branch' -d 'branch' -xa '(__tool_complete)'`,
+		)
+
+		if strings.Contains(script(t, "bash"), "__complete") {
+			t.Error("completion function without dynamic flags")
+		}
+	})
+
+	t.Run("unsupported shell", func(t *testing.T) {
+		yesErr(t, setup().Completion("tcsh", &strings.Builder{}))
+	})
+
+	t.Run("missing program name", func(t *testing.T) {
+		yesErr(t, NewParser().Completion("bash", &strings.Builder{}))
+	})
+}
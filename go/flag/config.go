@@ -0,0 +1,232 @@
+// This file implements the configuration file layer, used for flags absent from the arguments and
+// from the environment.
+
+package flag
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+)
+
+// WithConfigFlag registers a flag with the given name, used to give the path of a JSON
+// configuration file that is loaded with LoadJSON after the arguments are processed.
+func WithConfigFlag(name string) func(*Parser) {
+	return func(cfg *Parser) {
+		cfg.configPath = new(string)
+		config := cfg.String(name, cfg.configPath, "Load flags from a JSON configuration file")
+		config.Persistent()
+		cfg.builtins = append(cfg.builtins, config.(flag))
+	}
+}
+
+// WithLenientConfig ignores the configuration keys that do not match any flag.
+func WithLenientConfig() func(*Parser) {
+	return func(cfg *Parser) {
+		cfg.lenientConfig = true
+	}
+}
+
+// configValue is a value read from a configuration file.
+type configValue struct {
+	key    string   // Path of the value in the configuration file, used for error reporting.
+	values []string // Arguments to consume.
+}
+
+// LoadJSON reads a JSON object whose keys are canonical flag names and keeps its values until the
+// end of the next parse, where they are consumed by the flags that were not otherwise set.
+// Arrays can only be given to flags consuming multiple values and objects are used to configure
+// map flags, e.g. `{"label": {"env": "prod"}}`, or the subcommand of the same name.
+// The configuration previously loaded is discarded.
+func (par *Parser) LoadJSON(reader io.Reader) error {
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil {
+		return fmt.Errorf("cannot decode configuration: %w", err)
+	}
+
+	par.clearConfig()
+	return par.loadObject("", object)
+}
+
+// clearConfig discards the configuration loaded by the parser and its subcommands.
+func (par *Parser) clearConfig() {
+	for _, cmd := range par.tree() {
+		cmd.config = nil
+	}
+}
+
+// loadConfigFlag loads the configuration file given by the config flag, if any.
+func (par *Parser) loadConfigFlag() error {
+	if par.configPath == nil || *par.configPath == "" {
+		return nil
+	}
+
+	file, err := os.Open(*par.configPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close() //nolint:errcheck // Read-only.
+
+	if err := par.LoadJSON(file); err != nil {
+		return fmt.Errorf("%s: %w", *par.configPath, err)
+	}
+
+	return nil
+}
+
+// loadObject stores the values of a configuration object, prefix being the path of the object.
+func (par *Parser) loadObject(prefix string, object map[string]any) error {
+	var errs []error
+
+	for _, name := range slices.Sorted(maps.Keys(object)) {
+		key := prefix + name
+		if err := par.loadValue(key, name, object[name]); err != nil {
+			errs = append(errs, fmt.Errorf("config key %q: %w", key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// keyedFlag is implemented by the flags consuming key-value pairs, which are configured by objects.
+type keyedFlag interface {
+	flag
+
+	// separator returns the string separating a key from its value.
+	separator() string
+}
+
+// loadValue stores the value associated to a configuration key.
+func (par *Parser) loadValue(key, name string, value any) error {
+	if object, isObject := value.(map[string]any); isObject {
+		if flg, isKeyed := par.visible(name).(keyedFlag); isKeyed {
+			return par.loadPairs(key, flg, object)
+		}
+
+		cmd := par.command(name)
+		if cmd == nil {
+			return par.unknownKey(fmt.Errorf("unknown command"))
+		}
+
+		return cmd.loadObject(key+".", object)
+	}
+
+	flg := par.visible(name)
+	if flg == nil {
+		return par.unknownKey(fmt.Errorf("unknown flag"))
+	}
+
+	if value == nil { // Null means unset.
+		return nil
+	}
+
+	elements, isArray := value.([]any)
+	if !isArray {
+		elements = []any{value}
+	} else if flg.arity() >= 0 {
+		return fmt.Errorf("%s (%s) cannot consume an array", name, flg.kind())
+	}
+
+	values := make([]string, len(elements))
+	for i, element := range elements {
+		var err error
+		if values[i], err = scalar(element); err != nil {
+			return err
+		}
+	}
+
+	par.store(flg, configValue{key: key, values: values})
+	return nil
+}
+
+// loadPairs stores the entries of a configuration object as the key-value pairs of a map flag.
+func (par *Parser) loadPairs(key string, flg keyedFlag, object map[string]any) error {
+	var values []string
+	for _, name := range slices.Sorted(maps.Keys(object)) {
+		value, err := scalar(object[name])
+		if err != nil {
+			return fmt.Errorf("entry %q: %w", name, err)
+		}
+
+		values = append(values, name+flg.separator()+value)
+	}
+
+	par.store(flg, configValue{key: key, values: values})
+	return nil
+}
+
+// store associates a configuration value to a flag.
+func (par *Parser) store(flg flag, value configValue) {
+	if par.config == nil {
+		par.config = map[flag]configValue{}
+	}
+
+	par.config[flg] = value
+}
+
+// scalar returns the string representation of a JSON scalar, i.e. a string, number or boolean.
+func scalar(element any) (string, error) {
+	switch concrete := element.(type) {
+	case string:
+		return concrete, nil
+	case json.Number:
+		return concrete.String(), nil
+	case bool:
+		return fmt.Sprint(concrete), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v", element)
+	}
+}
+
+// unknownKey returns the given error unless the parser is lenient.
+func (par *Parser) unknownKey(err error) error {
+	if par.lenientConfig {
+		return nil
+	}
+
+	return err
+}
+
+// consumeConfig feeds the configuration value of the flag, if any.
+// Persistent flags can be configured by the selected subcommands, the innermost value winning.
+func (par *Parser) consumeConfig(flg flag) error {
+	var (
+		cfg    configValue
+		exists bool
+	)
+
+	for cmd := par.leaf(); !exists && cmd != par.parent; cmd = cmd.parent {
+		cfg, exists = cmd.config[flg]
+	}
+
+	if !exists {
+		return nil
+	}
+
+	for _, value := range cfg.values {
+		if err := flg.consume(value); err != nil {
+			return fmt.Errorf("when consuming %s (%s) from config key %q: %w",
+				flg.names()[0], flg.kind(), cfg.key, err)
+		}
+	}
+
+	return nil
+}
+
+// flag returns the flag with the given canonical name, or nil if it does not exist.
+func (par *Parser) flag(name string) flag {
+	for _, flg := range par.canonical {
+		if flg.names()[0] == name {
+			return flg
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package flag
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParser_LoadJSON(t *testing.T) {
+	type dests struct {
+		port  int
+		host  string
+		debug bool
+		ids   []int
+	}
+
+	setup := func(opts ...ParserOpt) (*Parser, *dests) {
+		var res dests
+		par := NewParser(opts...)
+		par.Int("port", &res.port, "port").Default(80)
+		par.String("host", &res.host, "host").Default("localhost")
+		par.Bool("debug", &res.debug, "debug")
+		par.IntSlice("ids", &res.ids, "identifiers")
+		return par, &res
+	}
+
+	t.Run("values", func(t *testing.T) {
+		par, res := setup()
+		noErr(t, par.LoadJSON(strings.NewReader(
+			`{"port": 8080, "host": "example.com", "debug": true, "ids": [1, 2]}`)))
+		noErr(t, par.Parse(nil))
+		eq(t, dests{8080, "example.com", true, []int{1, 2}}, *res)
+	})
+
+	t.Run("precedence", func(t *testing.T) {
+		t.Setenv("TEST_HOST", "env.com")
+		par, res := setup(WithEnvPrefix("TEST"))
+		noErr(t, par.LoadJSON(strings.NewReader(`{"port": 8080, "host": "example.com"}`)))
+		noErr(t, par.Parse([]string{"--port", "23"}))
+		eq(t, 23, res.port)
+		eq(t, "env.com", res.host)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		par, _ := setup()
+		err := par.LoadJSON(strings.NewReader(`{"prot": 8080}`))
+		yesErr(t, err)
+		if err != nil && !strings.Contains(err.Error(), `"prot"`) {
+			t.Errorf("error does not mention the key: %v", err)
+		}
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		par, _ := setup(WithLenientConfig())
+		noErr(t, par.LoadJSON(strings.NewReader(`{"prot": 8080, "cmd": {}}`)))
+	})
+
+	t.Run("array to singleton", func(t *testing.T) {
+		par, _ := setup()
+		yesErr(t, par.LoadJSON(strings.NewReader(`{"port": [1, 2]}`)))
+	})
+
+	t.Run("decoding error", func(t *testing.T) {
+		par, _ := setup()
+		noErr(t, par.LoadJSON(strings.NewReader(`{"port": "eighty"}`)))
+		err := par.Parse(nil)
+		yesErr(t, err)
+		if err != nil && !strings.Contains(err.Error(), `config key "port"`) {
+			t.Errorf("error does not mention the key: %v", err)
+		}
+	})
+
+	t.Run("subcommand", func(t *testing.T) {
+		var jobs int
+		par, _ := setup()
+		par.Command("run", "").Int("jobs", &jobs, "jobs")
+		err := par.LoadJSON(strings.NewReader(`{"run": {"jobs": 4, "job": 3}}`))
+		if err == nil || !strings.Contains(err.Error(), `"run.job"`) {
+			t.Errorf("expected an error about run.job, got %v", err)
+		}
+		noErr(t, par.Parse([]string{"run"}))
+		eq(t, 4, jobs)
+	})
+
+	t.Run("inherited flag", func(t *testing.T) {
+		var quiet, verbose bool
+		par, _ := setup()
+		par.Bool("quiet", &quiet, "quiet").Persistent()
+		par.Bool("verbose", &verbose, "verbose").Persistent()
+		par.Command("run", "")
+		noErr(t, par.LoadJSON(strings.NewReader(
+			`{"verbose": false, "run": {"quiet": true, "verbose": true}}`)))
+		noErr(t, par.Parse([]string{"run"}))
+		eq(t, true, quiet)
+		eq(t, true, verbose)
+	})
+
+	t.Run("map flag", func(t *testing.T) {
+		var (
+			labels map[string]string
+			limits map[string]int
+		)
+		par, _ := setup()
+		par.StringMap("label", &labels, "labels")
+		RegisterMap[String, Int](par, "limit", &limits, "limits", MapOptions{Separator: ":"})
+		noErr(t, par.LoadJSON(strings.NewReader(
+			`{"label": {"env": "prod", "team": "core"}, "limit": {"cpu": 2}}`)))
+		noErr(t, par.Parse(nil))
+		eq(t, map[string]string{"env": "prod", "team": "core"}, labels)
+		eq(t, map[string]int{"cpu": 2}, limits)
+		yesErr(t, par.LoadJSON(strings.NewReader(`{"label": {"env": ["prod"]}}`)))
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		par, res := setup()
+		par.Command("run", "").Int("jobs", new(int), "jobs")
+		noErr(t, par.LoadJSON(strings.NewReader(`{"port": 8080, "run": {"jobs": 4}}`)))
+		noErr(t, par.LoadJSON(strings.NewReader(`{"host": "example.com"}`)))
+		noErr(t, par.Parse(nil))
+		eq(t, 80, res.port)
+		eq(t, "example.com", res.host)
+		eq(t, 0, len(par.command("run").config))
+	})
+
+	t.Run("single parse", func(t *testing.T) {
+		par, res := setup()
+		noErr(t, par.LoadJSON(strings.NewReader(`{"host": "example.com"}`)))
+		noErr(t, par.Parse(nil))
+		eq(t, "example.com", res.host)
+		eq(t, 0, len(par.config))
+	})
+
+	t.Run("config flag", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		noErr(t, os.WriteFile(path, []byte(`{"port": 8080}`), 0o600))
+		par, res := setup(WithConfigFlag("config"))
+		noErr(t, par.Parse([]string{"--config", path}))
+		eq(t, 8080, res.port)
+	})
+}
@@ -0,0 +1,171 @@
+// This file implements the constraints on the relationships between flags, checked after parsing.
+
+package flag
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mooss/bagend/go/fun/eager/lie"
+)
+
+// constraint is a relationship between flags.
+// The constraints consider the flags that were given, switches being only given when enabled,
+// e.g. `--no-tls` does not give `--tls`.
+type constraint interface {
+	// check returns an error if the constraint is violated by the flags that were given.
+	check() error
+
+	// describe returns a short explanation of the constraint.
+	describe() string
+}
+
+//////////////////////
+// Constraint types //
+
+// exclusive is satisfied when at most one of its flags is given.
+type exclusive []flag
+
+func (ex exclusive) check() error {
+	if given := givenFlags(ex); len(given) > 1 {
+		return fmt.Errorf("%s are mutually exclusive", enumerate(given))
+	}
+
+	return nil
+}
+
+func (ex exclusive) describe() string {
+	return enumerate(ex) + " are mutually exclusive"
+}
+
+// together is satisfied when either none or all of its flags are given.
+type together []flag
+
+func (tog together) check() error {
+	if given := givenFlags(tog); len(given) > 0 && len(given) < len(tog) {
+		return fmt.Errorf("%s must be given together", enumerate(tog))
+	}
+
+	return nil
+}
+
+func (tog together) describe() string {
+	return enumerate(tog) + " must be given together"
+}
+
+// requiredIf is satisfied when target is given or condition is not given.
+type requiredIf struct {
+	target, condition flag
+}
+
+func (req requiredIf) check() error {
+	if req.condition.given() && !req.target.given() {
+		return fmt.Errorf("%s is required by %s",
+			name2flag(req.target.names()[0]), name2flag(req.condition.names()[0]))
+	}
+
+	return nil
+}
+
+func (req requiredIf) describe() string {
+	return fmt.Sprintf("%s is required by %s",
+		name2flag(req.target.names()[0]), name2flag(req.condition.names()[0]))
+}
+
+//////////////////
+// Declarations //
+
+// Exclusive declares that at most one of the flags can be given.
+func (par *Parser) Exclusive(names ...string) {
+	if flags := par.constrained(names, 2); flags != nil {
+		par.constraints = append(par.constraints, exclusive(flags))
+	}
+}
+
+// Together declares that the flags must either be all given or all absent.
+func (par *Parser) Together(names ...string) {
+	if flags := par.constrained(names, 2); flags != nil {
+		par.constraints = append(par.constraints, together(flags))
+	}
+}
+
+// RequiredIf declares that the target flag is required when the condition flag is given.
+func (par *Parser) RequiredIf(target, condition string) {
+	if flags := par.constrained([]string{target, condition}, 2); flags != nil {
+		par.constraints = append(par.constraints, requiredIf{flags[0], flags[1]})
+	}
+}
+
+// constrained returns the flags designated by canonical names, or nil if they cannot be
+// constrained together, in which case a definition error is registered.
+func (par *Parser) constrained(names []string, minimum int) []flag {
+	if len(names) < minimum {
+		par.errdef(fmt.Errorf("constraint on %v requires at least %d flags", names, minimum))
+		return nil
+	}
+
+	res := make([]flag, len(names))
+	for i, name := range names {
+		res[i] = par.visible(name)
+		if res[i] == nil {
+			par.errdef(fmt.Errorf("constraint on unknown flag %s", name))
+			return nil
+		}
+	}
+
+	return res
+}
+
+// checkConstraints returns the violations of the constraints of the parser.
+func (par *Parser) checkConstraints() []error {
+	var errs []error
+	for _, cns := range par.constraints {
+		if err := cns.check(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+///////////////
+// Utilities //
+
+// visible returns the flag with the given canonical name, either registered to the parser or
+// inherited from its ancestors, or nil if it does not exist.
+func (par *Parser) visible(name string) flag {
+	if flg := par.flag(name); flg != nil {
+		return flg
+	}
+
+	for _, flg := range par.inherited() {
+		if flg.names()[0] == name {
+			return flg
+		}
+	}
+
+	return nil
+}
+
+// givenFlags returns the flags that were given.
+func givenFlags(flags []flag) []flag {
+	var res []flag
+	for _, flg := range flags {
+		if flg.given() {
+			res = append(res, flg)
+		}
+	}
+
+	return res
+}
+
+// enumerate returns a human-readable enumeration of flags, e.g. `--a, --b and --c`.
+func enumerate(flags []flag) string {
+	names := lie.Map(func(flg flag) string { return name2flag(flg.names()[0]) }, flags)
+	last := len(names) - 1
+	if last == 0 {
+		return names[0]
+	}
+
+	return strings.Join(names[:last], ", ") + " and " + names[last]
+}
@@ -0,0 +1,99 @@
+package flag
+
+import "testing"
+
+func TestParser_Constraints(t *testing.T) {
+	setup := func() *Parser {
+		par := NewParser()
+		par.String("output", new(string), "output file")
+		par.Bool("stdout", new(bool), "write to stdout")
+		par.String("user", new(string), "user name")
+		par.String("password", new(string), "password")
+		par.Bool("tls", new(bool), "enable tls")
+		par.String("tls-key", new(string), "tls key")
+
+		par.Exclusive("output", "stdout")
+		par.Together("user", "password")
+		par.RequiredIf("tls-key", "tls")
+		return par
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"none", nil, ""},
+		{"exclusive", []string{"--output", "out", "--stdout"},
+			"--output and --stdout are mutually exclusive"},
+		{"exclusive ok", []string{"--stdout"}, ""},
+		{"exclusive disabled switch", []string{"--stdout=false", "--output", "x"}, ""},
+		{"together", []string{"--user", "me"},
+			"--user and --password must be given together"},
+		{"together ok", []string{"--user", "me", "--password", "secret"}, ""},
+		{"required if", []string{"--tls"}, "--tls-key is required by --tls"},
+		{"required if ok", []string{"--tls-key", "key"}, ""},
+		{"required if negated", []string{"--no-tls"}, ""},
+		{"multiple", []string{"--tls", "--password", "secret"},
+			"--user and --password must be given together\n--tls-key is required by --tls"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := setup().Parse(tt.args)
+			if tt.expected == "" {
+				noErr(t, err)
+				return
+			}
+
+			yesErr(t, err)
+			if err != nil {
+				eq(t, tt.expected, err.Error())
+			}
+		})
+	}
+
+	t.Run("unknown flag", func(t *testing.T) {
+		par := NewParser()
+		par.Bool("a", new(bool), "a")
+		par.Exclusive("a", "b")
+		yesErr(t, par.Parse(nil))
+	})
+
+	t.Run("single flag", func(t *testing.T) {
+		par := NewParser()
+		par.Bool("a", new(bool), "a")
+		par.Together("a")
+		yesErr(t, par.Parse(nil))
+	})
+
+	t.Run("inherited flag", func(t *testing.T) {
+		par := NewParser()
+		par.Bool("quiet", new(bool), "quiet").Persistent()
+		cmd := par.Command("run", "")
+		cmd.Bool("verbose", new(bool), "verbose")
+		cmd.Exclusive("quiet", "verbose")
+		noErr(t, par.Parse([]string{"--quiet"}))
+		yesErr(t, par.Parse([]string{"run", "--quiet", "--verbose"}))
+	})
+
+	t.Run("help", func(t *testing.T) {
+		par := NewParser()
+		par.Int("a", new(int), "a")
+		par.Int("b", new(int), "b")
+		par.Int("c", new(int), "c")
+		par.Exclusive("a", "b", "c")
+		par.RequiredIf("a", "b")
+		eq(t, `Usage:
+
+Flags:
+  -a INT  a
+  -b INT  b
+  -c INT  c
+
+Constraints:
+  -a, -b and -c are mutually exclusive
+  -a is required by -b
+`, par.Help())
+	})
+}
@@ -0,0 +1,95 @@
+// This file implements flags counting their occurrences, e.g. `-vvv`.
+
+package flag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// counterFlag represents a flag incremented each time it is given.
+// It implements both the flag and FluentFlag interfaces.
+type counterFlag struct {
+	// flagBase implements the FluentFlag interface and part of the flag interface.
+	flagBase[int]
+	limit int // Maximum value of the counter, 0 meaning no maximum.
+}
+
+///////////////////////////////////////////
+// Rest of flag interface implementation //
+
+// consume increments the counter by values prefixed with `+` and sets it to the other values.
+func (cf *counterFlag) consume(value string) error {
+	increment, relative := strings.CutPrefix(value, "+")
+
+	decoded, err := strconv.Atoi(increment)
+	if err != nil {
+		return err
+	}
+
+	if decoded < 0 {
+		return fmt.Errorf("counter cannot be negative")
+	}
+
+	if relative && cf.alreadySet {
+		decoded += *cf.dest
+	}
+
+	if cf.limit > 0 {
+		decoded = min(decoded, cf.limit)
+	}
+
+	if err := cf.validate(decoded); err != nil {
+		return err
+	}
+
+	*cf.dest = decoded
+	cf.alreadySet = true
+
+	return nil
+}
+
+func (*counterFlag) arity() int {
+	return 0
+}
+
+func (cf *counterFlag) given() bool {
+	return cf.alreadySet && *cf.dest > 0
+}
+
+func (*counterFlag) implicit() string {
+	return "+1"
+}
+
+func (*counterFlag) negation() string {
+	return "" // Counters are reset with an explicit value, e.g. `--verbose=0`.
+}
+
+func (*counterFlag) placeholder() string {
+	return ""
+}
+
+func (*counterFlag) choices() []string {
+	return nil
+}
+
+func (*counterFlag) decodes(string) bool {
+	return false // Counters never wait for a value.
+}
+
+func (*counterFlag) files() fileCompletion {
+	return noFiles
+}
+
+func (cf *counterFlag) defaultValue() string {
+	if cf.hasDefault {
+		return strconv.Itoa(cf.def)
+	}
+
+	return ""
+}
+
+func (*counterFlag) kind() string {
+	return "counter"
+}
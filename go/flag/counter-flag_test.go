@@ -0,0 +1,55 @@
+package flag
+
+import "testing"
+
+func TestParser_Counter(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		args     []string
+		expected int
+	}{
+		{"absent", 0, nil, 0},
+		{"repeated", 0, []string{"--verbose", "-v", "--verbose"}, 3},
+		{"bundled", 0, []string{"-vvv", "-v"}, 4},
+		{"explicit", 0, []string{"--verbose=3"}, 3},
+		{"explicit then increment", 0, []string{"--verbose=3", "-v"}, 4},
+		{"relative", 0, []string{"-v", "--verbose=+2"}, 3},
+		{"reset", 0, []string{"-vv", "--verbose=0"}, 0},
+		{"capped", 2, []string{"-vvv"}, 2},
+		{"capped explicit", 2, []string{"--verbose=5"}, 2},
+		{"bundled with other flags", 0, []string{"-vqv"}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var verbose int
+			par := NewParser(WithShortBundling())
+			par.Counter("verbose", &verbose, tt.limit, "verbosity").Alias("v")
+			par.Bool("quiet", new(bool), "quiet").Alias("q")
+			noErr(t, par.Parse(tt.args))
+			eq(t, tt.expected, verbose)
+		})
+	}
+
+	for _, args := range [][]string{{"--verbose=-1"}, {"--verbose=many"}, {"--no-verbose"}} {
+		t.Run("invalid "+args[0], func(t *testing.T) {
+			par := NewParser()
+			par.Counter("verbose", new(int), 0, "verbosity")
+			yesErr(t, par.Parse(args))
+		})
+	}
+
+	t.Run("default", func(t *testing.T) {
+		var verbose int
+		par := NewParser()
+		par.Counter("verbose", &verbose, 0, "verbosity").Default(1).Alias("v")
+		noErr(t, par.Parse(nil))
+		eq(t, 1, verbose)
+		eq(t, `Usage:
+
+Flags:
+  --verbose, -v  verbosity (default: 1)
+`, par.Help())
+	})
+}
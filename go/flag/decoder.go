@@ -2,7 +2,11 @@
 
 package flag
 
-import "strconv"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 // Decoder is the single interface that must be implemented to add support for an arbitrary flag
 // type.
@@ -11,22 +15,195 @@ type Decoder[T any] interface {
 	Decode(string) (T, error)
 }
 
-// Int implements Decoder[int].
-type Int struct{}
-
-func (Int) Decode(source string) (int, error) {
-	return strconv.Atoi(source)
+// Formatter is an optional interface for decoders, formatting values back into a decodable form.
+// It is used to display default values in help pages.
+type Formatter[T any] interface {
+	Format(T) string
 }
 
-// String implements Decoder[string].
+// String implements Decoder[string] and Placeholder.
 type String struct{}
 
 func (String) Decode(source string) (string, error) {
 	return source, nil
 }
 
+func (String) Placeholder() string { return "STRING" }
+
+// Path implements Decoder[string], Placeholder and FilePath.
+// It accepts any non-empty path, its only purpose is to enable file completion.
+type Path struct {
+	// Dir restricts the completion to directories.
+	Dir bool
+}
+
+func (Path) Decode(source string) (string, error) {
+	if source == "" {
+		return "", fmt.Errorf("empty path")
+	}
+
+	return source, nil
+}
+
+func (dec Path) Placeholder() string {
+	if dec.Dir {
+		return "DIR"
+	}
+
+	return "PATH"
+}
+
+func (dec Path) Directory() bool { return dec.Dir }
+
+// Placeholder is an optional interface for decoders, describing the expected value in help pages.
+type Placeholder interface {
+	Placeholder() string
+}
+
+// Enumerable is an optional interface for decoders accepting a finite set of values.
+type Enumerable interface {
+	// Enumerate returns the accepted values.
+	Enumerate() []string
+}
+
+// FilePath is an optional interface for decoders of file paths, whose values are completed with
+// file names by the shells.
+type FilePath interface {
+	// Directory returns true when only directories must be completed.
+	Directory() bool
+}
+
+// Switch is an optional interface for decoders whose flags can be given without a value.
+type Switch interface {
+	// Implicit returns the value to decode when the flag is given without a value.
+	Implicit() string
+}
+
+// Negatable is an optional interface for switch decoders, whose flags can be negated by prefixing
+// their name with `no-`.
+type Negatable interface {
+	// Negated returns the value to decode when the negated flag is given.
+	Negated() string
+}
+
+// Bool implements Decoder[bool], Switch and Negatable.
 type Bool struct{}
 
 func (Bool) Decode(source string) (bool, error) {
 	return strconv.ParseBool(source)
 }
+
+func (Bool) Implicit() string { return "true" }
+func (Bool) Negated() string  { return "false" }
+
+// Optional holds a value that may not have been given.
+type Optional[T any] struct {
+	Value T
+	Set   bool
+}
+
+// OptionalBool implements Decoder[Optional[bool]], Switch, Negatable and Formatter.
+// It can be used to distinguish an absent flag from a flag explicitly set to false.
+type OptionalBool struct{}
+
+func (OptionalBool) Decode(source string) (Optional[bool], error) {
+	value, err := strconv.ParseBool(source)
+	return Optional[bool]{Value: value, Set: err == nil}, err
+}
+
+func (OptionalBool) Implicit() string { return "true" }
+func (OptionalBool) Negated() string  { return "false" }
+
+func (OptionalBool) Format(value Optional[bool]) string {
+	if !value.Set {
+		return ""
+	}
+
+	return strconv.FormatBool(value.Value)
+}
+
+// Choice implements Decoder[T], Placeholder and Enumerable for a finite set of values.
+// A value is matched using its string representation, as given by fmt.Sprint.
+type Choice[T comparable] struct {
+	Values     []T
+	IgnoreCase bool
+}
+
+func (ch Choice[T]) Decode(source string) (T, error) {
+	for _, value := range ch.Values {
+		repr := fmt.Sprint(value)
+		if repr == source || ch.IgnoreCase && strings.EqualFold(repr, source) {
+			return value, nil
+		}
+	}
+
+	var zero T
+	return zero, fmt.Errorf(
+		"invalid value %q, expected one of %s", source, strings.Join(ch.Enumerate(), ", "))
+}
+
+func (ch Choice[T]) Placeholder() string {
+	return "{" + strings.Join(ch.Enumerate(), ",") + "}"
+}
+
+func (ch Choice[T]) Enumerate() []string {
+	res := make([]string, len(ch.Values))
+	for i, value := range ch.Values {
+		res[i] = fmt.Sprint(value)
+	}
+
+	return res
+}
+
+///////////////
+// Utilities //
+
+// placeholder returns the placeholder of a decoder, or an empty string if it does not have one.
+func placeholder(decoder any) string {
+	if ph, ok := decoder.(Placeholder); ok {
+		return ph.Placeholder()
+	}
+
+	return ""
+}
+
+// formatValue formats a value with its decoder when it implements Formatter, or with
+// fmt.Sprint otherwise.
+func formatValue[T any](decoder any, value T) string {
+	if fmtr, ok := decoder.(Formatter[T]); ok {
+		return fmtr.Format(value)
+	}
+
+	return fmt.Sprint(value)
+}
+
+// fileCompletion describes whether the values of a flag are completed with file names.
+type fileCompletion int
+
+const (
+	noFiles fileCompletion = iota
+	anyFiles
+	dirsOnly
+)
+
+// files returns how the values of a decoder are completed with file names.
+func files(decoder any) fileCompletion {
+	fp, ok := decoder.(FilePath)
+	switch {
+	case !ok:
+		return noFiles
+	case fp.Directory():
+		return dirsOnly
+	default:
+		return anyFiles
+	}
+}
+
+// choices returns the values accepted by a decoder, or nil if they are not enumerable.
+func choices(decoder any) []string {
+	if enum, ok := decoder.(Enumerable); ok {
+		return enum.Enumerate()
+	}
+
+	return nil
+}
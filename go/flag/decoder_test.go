@@ -0,0 +1,171 @@
+package flag
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type format string
+
+type level int
+
+func (lvl level) String() string {
+	return [...]string{"debug", "info", "error"}[lvl]
+}
+
+func TestChoice(t *testing.T) {
+	formats := Choice[format]{Values: []format{"json", "yaml", "table"}}
+	levels := Choice[level]{Values: []level{0, 1, 2}, IgnoreCase: true}
+
+	t.Run("named string", func(t *testing.T) {
+		got, err := formats.Decode("yaml")
+		noErr(t, err)
+		eq(t, format("yaml"), got)
+	})
+
+	t.Run("case sensitive", func(t *testing.T) {
+		_, err := formats.Decode("YAML")
+		yesErr(t, err)
+		if err != nil && !strings.Contains(err.Error(), "json, yaml, table") {
+			t.Errorf("error does not list the choices: %v", err)
+		}
+	})
+
+	t.Run("stringer ignoring case", func(t *testing.T) {
+		got, err := levels.Decode("INFO")
+		noErr(t, err)
+		eq(t, level(1), got)
+	})
+
+	t.Run("enumeration", func(t *testing.T) {
+		eq(t, []string{"debug", "info", "error"}, levels.Enumerate())
+		eq(t, "{json,yaml,table}", formats.Placeholder())
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		var got format
+		par := NewParser()
+		RegisterWith(par, formats, "format", &got, "output format").Alias("f").Default("json")
+		noErr(t, par.Parse(nil))
+		eq(t, format("json"), got)
+		noErr(t, par.Parse([]string{"-f", "table"}))
+		eq(t, format("table"), got)
+		yesErr(t, par.Parse([]string{"-f", "xml"}))
+	})
+
+	t.Run("help", func(t *testing.T) {
+		par := NewParser()
+		par.Choice("format", new(string), []string{"json", "yaml"}, "output format").Alias("f")
+		RegisterSliceWith(par, levels, "level", new([]level), "levels")
+		eq(t, `Usage:
+
+Flags:
+  --format, -f {json,yaml}       output format
+  --level {debug,info,error}...  levels
+`, par.Help())
+	})
+}
+
+func decodeExpect[D Decoder[T], T any](t *testing.T, decoder D, source string, expected T) {
+	t.Helper()
+	got, err := decoder.Decode(source)
+	noErr(t, err)
+	eq(t, expected, got)
+}
+
+func TestNumericDecoders(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		decodeExpect(t, Int8{}, "-128", int8(-128))
+		decodeExpect(t, Int16{}, "32767", int16(32767))
+		decodeExpect(t, Int32{}, "-5", int32(-5))
+		decodeExpect(t, Int64{}, "9223372036854775807", int64(9223372036854775807))
+		decodeExpect(t, Uint{}, "42", uint(42))
+		decodeExpect(t, Uint8{}, "255", uint8(255))
+		decodeExpect(t, Uint16{}, "65535", uint16(65535))
+		decodeExpect(t, Uint32{}, "4294967295", uint32(4294967295))
+		decodeExpect(t, Uint64{}, "18446744073709551615", uint64(18446744073709551615))
+		decodeExpect(t, Float32{}, "1.5", float32(1.5))
+		decodeExpect(t, Float64{}, "-2.5e3", -2500.)
+	})
+
+	t.Run("literals", func(t *testing.T) {
+		decodeExpect(t, Int{Literal: true}, "0xFF", 255)
+		decodeExpect(t, Int8{Literal: true}, "-0b1010", int8(-10))
+		decodeExpect(t, Uint16{Literal: true}, "0o755", uint16(0o755))
+		decodeExpect(t, Int64{Literal: true}, "1_000_000", int64(1000000))
+		decodeExpect(t, Float64{Literal: true}, "1_000.5", 1000.5)
+		decodeExpect(t, Float64{Literal: true}, "0x1p4", 16.)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, err := range []error{
+			second(Int{}.Decode("0xFF")),
+			second(Int{}.Decode("1_000")),
+			second(Uint{}.Decode("-1")),
+			second(Float64{}.Decode("1_000.5")),
+			second(Float64{}.Decode("0x1p4")),
+			second(Int{Literal: true}.Decode("1__0")),
+		} {
+			yesErr(t, err)
+		}
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		for _, err := range []error{
+			second(Int8{}.Decode("128")),
+			second(Uint8{Literal: true}.Decode("0x100")),
+			second(Int64{}.Decode("-9223372036854775809")),
+			second(Float32{}.Decode("1e39")),
+		} {
+			var overflow *OverflowError
+			if !errors.As(err, &overflow) {
+				t.Errorf("expected an overflow error, got %v", err)
+			}
+		}
+
+		eq(t, "128 overflows int8", second(Int8{}.Decode("128")).Error())
+	})
+
+	t.Run("flags", func(t *testing.T) {
+		var (
+			small  int8
+			ratios []float64
+		)
+		par := NewParser()
+		par.Int8("small", &small, "small number")
+		par.Float64Slice("ratio", &ratios, "ratios")
+		noErr(t, par.Parse([]string{"--small", "-3", "--ratio", "0.5", "--ratio", "-1"}))
+		eq(t, int8(-3), small)
+		eq(t, []float64{0.5, -1}, ratios)
+		yesErr(t, par.Parse([]string{"--small", "300"}))
+	})
+
+	t.Run("literal flags", func(t *testing.T) {
+		var (
+			mask  int64
+			bytes []uint8
+		)
+		par := NewParser()
+		RegisterWith(par, Int64{Literal: true}, "mask", &mask, "bit mask")
+		RegisterSliceWith(par, Uint8{Literal: true}, "bytes", &bytes, "bytes")
+		noErr(t, par.Parse([]string{"--mask", "-0x_ff", "--bytes", "0b1", "0o17"}))
+		eq(t, int64(-0xff), mask)
+		eq(t, []uint8{1, 0o17}, bytes)
+	})
+}
+
+// second returns its second argument.
+func second[T any](_ T, err error) error {
+	return err
+}
+
+func TestPath(t *testing.T) {
+	_, err := Path{}.Decode("")
+	yesErr(t, err)
+
+	path, err := Path{}.Decode("dir/file")
+	noErr(t, err)
+	eq(t, "dir/file", path)
+	eq(t, "DIR", Path{Dir: true}.Placeholder())
+}
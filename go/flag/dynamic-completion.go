@@ -0,0 +1,197 @@
+// This file implements the dynamic completion, used by shells to complete values that depend on
+// the runtime state of the program.
+//
+// The shell calls the program with `__complete INDEX ARGS...`, where ARGS are the arguments
+// following the program name and INDEX is the position of the word being completed in ARGS.
+// The program then prints one candidate per line, optionally followed by a tab and a description,
+// and a final line made of a colon followed by a directive:
+//   - none: no other completion must be done.
+//   - files: file names must be completed.
+//   - dirs: directory names must be completed.
+//
+// For example, `tool __complete 1 remote --` can print:
+//
+//	--jobs	parallel jobs
+//	--help	Print this help page
+//	:none
+
+package flag
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// completeCommand is the hidden argument triggering the dynamic completion.
+const completeCommand = "__complete"
+
+// Completion directives, telling the shell what to do on top of the candidates.
+const (
+	completeNone  = "none"
+	completeFiles = "files"
+	completeDirs  = "dirs"
+)
+
+// candidate is a possible completion of a word.
+type candidate struct {
+	value       string
+	description string
+}
+
+// complete prints the completion candidates of a partial command line and exits.
+func (par *Parser) complete(arguments []string) error {
+	if len(arguments) == 0 {
+		return errors.New("completion requires the index of the word to complete")
+	}
+
+	index, err := strconv.Atoi(arguments[0])
+	words := arguments[1:]
+	if err != nil || index < 0 || index > len(words) {
+		return fmt.Errorf("invalid completion index %q", arguments[0])
+	}
+
+	current := ""
+	if index < len(words) {
+		current = words[index]
+	}
+
+	candidates, directive, err := par.candidates(words[:index], current)
+	if err != nil {
+		return err
+	}
+
+	for _, cand := range candidates {
+		if cand.description == "" {
+			fmt.Fprintln(par.output, cand.value)
+		} else {
+			fmt.Fprintln(par.output, cand.value+"\t"+cand.description)
+		}
+	}
+
+	fmt.Fprintln(par.output, ":"+directive)
+	par.exit(0)
+	return nil
+}
+
+// candidates returns the completions of the current word, given the words preceding it.
+// The preceding words are interpreted leniently, unknown flags being ignored.
+func (par *Parser) candidates(preceding []string, current string) ([]candidate, string, error) {
+	flags, err := par.validateAndExpand()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var (
+		cmd      = par
+		pending  flag // Flag waiting for a value.
+		dispatch = len(par.commands) > 0
+	)
+
+	for _, word := range preceding {
+		if pending != nil {
+			pending = nil
+			continue
+		}
+
+		if word == "--" { // Only positional arguments can follow.
+			return nil, completeFiles, nil
+		}
+
+		if strings.HasPrefix(word, "-") && word != "-" {
+			if uses, err := flags.resolve(word, cmd.bundling); err == nil {
+				last := uses[len(uses)-1]
+				if !last.attached && last.flg.arity() != 0 {
+					pending = last.flg
+				}
+			}
+
+			continue
+		}
+
+		if sub := cmd.command(word); dispatch && sub != nil {
+			inherited := flags.persistent()
+			flags, _ = sub.flags.expand()
+			for flagname, flg := range inherited {
+				_ = flags.add(flagname, flg)
+			}
+
+			cmd = sub
+			dispatch = len(sub.commands) > 0
+			continue
+		}
+
+		dispatch = false // Only the first positional argument can select a command.
+	}
+
+	switch {
+	case pending != nil:
+		res, directive := values(pending, current, "")
+		return res, directive, nil
+
+	case strings.HasPrefix(current, "--") && strings.Contains(current, "="):
+		flagname, value, _ := strings.Cut(current, "=")
+		if flg := flags[flagname]; flg != nil && flg.arity() != 0 {
+			res, directive := values(flg, value, flagname+"=")
+			return res, directive, nil
+		}
+
+		return nil, completeNone, nil
+
+	case strings.HasPrefix(current, "-"):
+		var res []candidate
+		for _, flg := range cmd.completable() {
+			for _, spelling := range spellings(flg) {
+				if strings.HasPrefix(spelling, current) {
+					res = append(res, candidate{spelling, flg.docline()})
+				}
+			}
+		}
+
+		return res, completeNone, nil
+
+	case dispatch:
+		var res []candidate
+		for _, sub := range cmd.commands {
+			if strings.HasPrefix(sub.name, current) {
+				res = append(res, candidate{sub.name, sub.summary()})
+			}
+		}
+
+		return res, completeNone, nil
+
+	default:
+		return nil, completeFiles, nil
+	}
+}
+
+// values returns the completions of the value of a flag, each prefixed by lead.
+// The completion function of the flag takes precedence over its choices and its file completion.
+func values(flg flag, prefix, lead string) ([]candidate, string) {
+	var (
+		res     []candidate
+		matches []string
+	)
+
+	switch {
+	case flg.completer() != nil:
+		matches = flg.completer()(prefix)
+	case flg.choices() != nil:
+		for _, choice := range flg.choices() {
+			if strings.HasPrefix(choice, prefix) {
+				matches = append(matches, choice)
+			}
+		}
+	case flg.files() == anyFiles:
+		return nil, completeFiles
+	case flg.files() == dirsOnly:
+		return nil, completeDirs
+	}
+
+	for _, match := range matches {
+		res = append(res, candidate{value: lead + match})
+	}
+
+	return res, completeNone
+}
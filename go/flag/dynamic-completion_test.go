@@ -0,0 +1,76 @@
+package flag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParser_DynamicCompletion(t *testing.T) {
+	complete := func(t *testing.T, arguments ...string) string {
+		t.Helper()
+		var (
+			out    strings.Builder
+			exited bool
+		)
+
+		par := NewParser(WithHelp("tool", "[FLAGS]"))
+		par.Choice("format", new(string), []string{"json", "yaml"}, "output format").Alias("f")
+		par.Path("config", new(string), "config file")
+		par.Bool("color", new(bool), "colored output")
+		par.Int("secret", new(int), "hidden flag").Hidden()
+		remote := par.Command("remote", "[FLAGS] NAME")
+		remote.String("host", new(string), "remote host").Complete(func(prefix string) []string {
+			return []string{prefix + "1.example.com", prefix + "2.example.com"}
+		})
+		remote.Command("add", "NAME URL")
+
+		par.output = &out
+		par.exit = func(int) { exited = true }
+		noErr(t, par.Parse(append([]string{"__complete"}, arguments...)))
+		eq(t, true, exited)
+		return out.String()
+	}
+
+	t.Run("flag names", func(t *testing.T) {
+		eq(t, "--format\toutput format\n:none\n", complete(t, "0", "--fo", "--co"))
+		eq(t, "--config\tconfig file\n--color\tcolored output\n:none\n",
+			complete(t, "0", "--co"))
+		eq(t, "--no-color\tcolored output\n:none\n", complete(t, "0", "--no"))
+	})
+
+	t.Run("choices", func(t *testing.T) {
+		eq(t, "json\nyaml\n:none\n", complete(t, "1", "--format"))
+		eq(t, "yaml\n:none\n", complete(t, "1", "-f", "y"))
+		eq(t, "--format=json\n:none\n", complete(t, "0", "--format=j"))
+	})
+
+	t.Run("files", func(t *testing.T) {
+		eq(t, ":files\n", complete(t, "1", "--config", "fi"))
+		eq(t, ":files\n", complete(t, "2", "unknown", "--color"))
+		eq(t, ":files\n", complete(t, "2", "--", "remote"))
+	})
+
+	t.Run("commands", func(t *testing.T) {
+		eq(t, "remote\t[FLAGS] NAME\n:none\n", complete(t, "0"))
+		eq(t, "remote\t[FLAGS] NAME\n:none\n", complete(t, "2", "--format", "json", "r"))
+		eq(t, "add\tNAME URL\n:none\n", complete(t, "1", "remote"))
+	})
+
+	t.Run("subcommand flags", func(t *testing.T) {
+		eq(t, "--host\tremote host\n--help\tPrint this help page\n:none\n",
+			complete(t, "1", "remote", "--h"))
+		eq(t, "ex1.example.com\nex2.example.com\n:none\n",
+			complete(t, "2", "remote", "--host", "ex"))
+	})
+
+	t.Run("hidden flags", func(t *testing.T) {
+		eq(t, ":none\n", complete(t, "0", "--se"))
+	})
+
+	t.Run("invalid index", func(t *testing.T) {
+		par := NewParser()
+		yesErr(t, par.Parse([]string{"__complete"}))
+		yesErr(t, par.Parse([]string{"__complete", "x"}))
+		yesErr(t, par.Parse([]string{"__complete", "2", "a"}))
+	})
+}
@@ -0,0 +1,93 @@
+// This file implements the environment variable layer, used for flags absent from the arguments.
+
+package flag
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// WithEnvPrefix binds every flag without an explicit environment variable to a variable derived
+// from its canonical name, e.g. `APP_LISTEN_PORT` for `--listen-port` with the `APP` prefix.
+// The names of the flags of subcommands also include the path of the subcommand, e.g.
+// `APP_REMOTE_ADD_TIMEOUT` for `tool remote add --timeout`.
+// The flags registered by the options, such as the help and config flags, are left unbound.
+func WithEnvPrefix(prefix string) func(*Parser) {
+	return func(cfg *Parser) {
+		cfg.envPrefix = prefix
+	}
+}
+
+// envVar returns the name of the environment variable bound to the given flag, or an empty string
+// if there is none.
+func (par *Parser) envVar(flg flag) string {
+	if name := flg.env(); name != "" {
+		return name
+	}
+
+	if par.envPrefix == "" || par.builtin(flg) {
+		return ""
+	}
+
+	// Inherited flags are named after the command that registered them.
+	owner := par
+	for owner.parent != nil && !slices.Contains(owner.canonical, flg) {
+		owner = owner.parent
+	}
+
+	parts := []string{flg.names()[0]}
+	for cmd := owner; cmd.parent != nil; cmd = cmd.parent {
+		parts = append([]string{cmd.name}, parts...)
+	}
+
+	name := strings.ReplaceAll(strings.Join(parts, "_"), "-", "_")
+	return par.envPrefix + "_" + strings.ToUpper(name)
+}
+
+// builtin returns true if the flag was registered by an option of the parser or of one of its
+// ancestors.
+func (par *Parser) builtin(flg flag) bool {
+	for cur := par; cur != nil; cur = cur.parent {
+		if slices.Contains(cur.builtins, flg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// consumeEnv feeds the value of the environment variable bound to the flag, if any.
+// Flags that can consume multiple values expect a comma-separated list, an empty variable
+// setting them to an empty value.
+func (par *Parser) consumeEnv(flg flag) error {
+	name := par.envVar(flg)
+	if name == "" {
+		return nil
+	}
+
+	value, exists := os.LookupEnv(name)
+	if !exists {
+		return nil
+	}
+
+	values := []string{value}
+	if flg.arity() < 0 {
+		if value == "" {
+			flg.empty()
+			return nil
+		}
+
+		values = strings.Split(value, ",")
+	}
+
+	for _, value := range values {
+		if err := flg.consume(value); err != nil {
+			return fmt.Errorf("when consuming %s (%s) from environment variable %s: %w",
+				flg.names()[0], flg.kind(), name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package flag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParser_Env(t *testing.T) {
+	t.Run("explicit variable", func(t *testing.T) {
+		t.Setenv("TEST_PORT", "8080")
+		var port int
+		par := NewParser()
+		par.Int("port", &port, "port").Env("TEST_PORT").Default(80)
+		noErr(t, par.Parse(nil))
+		eq(t, 8080, port)
+	})
+
+	t.Run("command line has precedence", func(t *testing.T) {
+		t.Setenv("TEST_PORT", "8080")
+		var port int
+		par := NewParser()
+		par.Int("port", &port, "port").Env("TEST_PORT").Default(80)
+		noErr(t, par.Parse([]string{"--port", "23"}))
+		eq(t, 23, port)
+	})
+
+	t.Run("default when unset", func(t *testing.T) {
+		var port int
+		par := NewParser(WithEnvPrefix("TEST"))
+		par.Int("port", &port, "port").Default(80)
+		noErr(t, par.Parse(nil))
+		eq(t, 80, port)
+	})
+
+	t.Run("derived name", func(t *testing.T) {
+		t.Setenv("TEST_LISTEN_ADDR", "localhost")
+		var addr string
+		par := NewParser(WithEnvPrefix("TEST"))
+		par.String("listen-addr", &addr, "address")
+		noErr(t, par.Parse(nil))
+		eq(t, "localhost", addr)
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		t.Setenv("TEST_IDS", "1,2,3")
+		var ids []int
+		par := NewParser(WithEnvPrefix("TEST"))
+		par.IntSlice("ids", &ids, "identifiers").Default([]int{4})
+		noErr(t, par.Parse(nil))
+		eq(t, []int{1, 2, 3}, ids)
+	})
+
+	t.Run("empty slice", func(t *testing.T) {
+		t.Setenv("TEST_IDS", "")
+		ids := []int{1}
+		par := NewParser(WithEnvPrefix("TEST"))
+		par.IntSlice("ids", &ids, "identifiers").Default([]int{4})
+		noErr(t, par.Parse(nil))
+		eq(t, 0, len(ids))
+	})
+
+	t.Run("subcommand", func(t *testing.T) {
+		t.Setenv("TEST_RUN_JOBS", "4")
+		t.Setenv("TEST_BUILD_JOBS", "2")
+		t.Setenv("TEST_QUIET", "true")
+		var runJobs, buildJobs int
+		var quiet bool
+		par := NewParser(WithEnvPrefix("TEST"))
+		par.Bool("quiet", &quiet, "quiet").Persistent()
+		par.Command("run", "").Int("jobs", &runJobs, "jobs")
+		par.Command("build", "").Int("jobs", &buildJobs, "jobs")
+		noErr(t, par.Parse([]string{"run"}))
+		eq(t, 4, runJobs)
+		eq(t, 0, buildJobs)
+		eq(t, true, quiet)
+		noErr(t, par.Parse([]string{"build"}))
+		eq(t, 2, buildJobs)
+	})
+
+	t.Run("builtin flags", func(t *testing.T) {
+		t.Setenv("TEST_HELP", "true")
+		t.Setenv("TEST_CONFIG", "missing.json")
+		par := NewParser(WithHelp("tool", ""), WithConfigFlag("config"), WithEnvPrefix("TEST"))
+		par.Command("run", "")
+		noErr(t, par.Parse([]string{"run"}))
+		eq(t, false, par.printHelp)
+		eq(t, false, strings.Contains(par.Help(), "TEST_HELP"))
+		eq(t, false, strings.Contains(par.Help(), "TEST_CONFIG"))
+	})
+
+	t.Run("decoding error", func(t *testing.T) {
+		t.Setenv("TEST_PORT", "eighty")
+		par := NewParser()
+		par.Int("port", new(int), "port").Env("TEST_PORT")
+		err := par.Parse(nil)
+		yesErr(t, err)
+		if err != nil && !strings.Contains(err.Error(), "environment variable TEST_PORT") {
+			t.Errorf("error does not mention the environment: %v", err)
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		par := NewParser(WithEnvPrefix("TEST"))
+		par.Int("port", new(int), "port").Env("PORT")
+		par.String("host", new(string), "host")
+		eq(t, `Usage:
+
+Flags:
+  --port INT     port [env: PORT]
+  --host STRING  host [env: TEST_HOST]
+`, par.Help())
+	})
+}
@@ -19,6 +19,8 @@ type sink interface {
 	consume(string) error
 
 	// arity returns the number of values the sink *must* consume.
+	// Flags of arity 0 are switches, they consume their implicit value unless a value is attached
+	// to them (e.g. `--flag=value`).
 	arity() int
 
 	// kind returns a short explanation of the kind of sink this is (singleton, slice or positional
@@ -36,8 +38,65 @@ type flag interface {
 	// docline returns the documentation line of the flag.
 	docline() string
 
-	// enforceDefault assigns the default value if the flag value has not been set.
-	enforceDefault()
+	// implicit returns the value consumed when a flag of arity 0 is given without a value.
+	implicit() string
+
+	// negation returns the value consumed by the `--no-` form of the flag, or an empty string when
+	// the flag cannot be negated.
+	negation() string
+
+	// placeholder returns a short description of the expected value, or an empty string.
+	placeholder() string
+
+	// choices returns the values accepted by the flag, or nil if they are not enumerable.
+	choices() []string
+
+	// decodes returns true if the value can be decoded, without consuming it.
+	decodes(string) bool
+
+	// files returns whether the values of the flag are completed with file names.
+	files() fileCompletion
+
+	// defaultValue returns the formatted default value, or an empty string if it cannot be
+	// formatted.
+	defaultValue() string
+
+	// enforceDefault assigns the default value if the flag value has not been set, returning an
+	// error if the default value is invalid.
+	enforceDefault() error
+
+	// empty sets the flag to its zero value, e.g. an empty slice, as if it had been given.
+	empty()
+
+	// check validates the complete value of a flag once all its values have been consumed.
+	// It is only needed by flags whose values are validated as a whole, e.g. slices and maps.
+	check() error
+
+	// persistent returns true when the flag is inherited by subcommands.
+	persistent() bool
+
+	// env returns the name of the environment variable explicitly bound to the flag, if any.
+	env() string
+
+	// isSet returns true when a value has been consumed since the flag was registered.
+	isSet() bool
+
+	// given returns true when the flag is set and, for switches, enabled, e.g. `--no-tls` and
+	// `--verbose=0` are set but not given.
+	given() bool
+
+	// isRequired returns true when the flag must be set by the arguments, the environment or the
+	// configuration.
+	isRequired() bool
+
+	// hidden returns true when the flag must be left out of the help page.
+	hidden() bool
+
+	// section returns the name of the help section of the flag, empty for the default section.
+	section() string
+
+	// completer returns the function completing the values of the flag, or nil.
+	completer() func(string) []string
 }
 
 // FluentFlag is the interface that is used for additional configuration of registered flags.
@@ -47,6 +106,33 @@ type FluentFlag[T any] interface {
 
 	// Default sets the given value as the default.
 	Default(T) FluentFlag[T]
+
+	// Persistent makes the flag available to all the subcommands of the parser.
+	Persistent() FluentFlag[T]
+
+	// Env binds the flag to an environment variable, used when the flag is absent from the
+	// arguments.
+	Env(string) FluentFlag[T]
+
+	// NoNegation disables the `--no-` form that is generated for negatable switches.
+	NoNegation() FluentFlag[T]
+
+	// Required makes parsing fail when the flag is not set, the default value is then ignored.
+	Required() FluentFlag[T]
+
+	// Validate adds a validator that is called on every consumed value and on the default value.
+	// The validators of slice and map flags are called once on the complete value.
+	Validate(func(T) error) FluentFlag[T]
+
+	// Hidden leaves the flag out of the help page.
+	Hidden() FluentFlag[T]
+
+	// Section groups the flag with the other flags of the same section in the help page.
+	Section(string) FluentFlag[T]
+
+	// Complete sets the function returning the candidate values of the flag matching a prefix,
+	// used by the dynamic completion.
+	Complete(func(prefix string) []string) FluentFlag[T]
 }
 
 //////////////
@@ -54,11 +140,20 @@ type FluentFlag[T any] interface {
 //////////////
 
 type flagBase[T any] struct {
-	def        T
-	dest       *T
-	docLine    string
-	namesStore []string
-	alreadySet bool
+	def          T
+	hasDefault   bool
+	dest         *T
+	docLine      string
+	namesStore   []string
+	alreadySet   bool
+	persist      bool
+	envName      string
+	noNegation   bool
+	required     bool
+	validators   []func(T) error
+	hide         bool
+	sectionName  string
+	completeFunc func(string) []string
 }
 
 /////////////////////////////////////////
@@ -71,6 +166,47 @@ func (fb *flagBase[T]) Alias(aliases ...string) FluentFlag[T] {
 
 func (fb *flagBase[T]) Default(value T) FluentFlag[T] {
 	fb.def = value
+	fb.hasDefault = true
+	return fb
+}
+
+func (fb *flagBase[T]) Persistent() FluentFlag[T] {
+	fb.persist = true
+	return fb
+}
+
+func (fb *flagBase[T]) Env(name string) FluentFlag[T] {
+	fb.envName = name
+	return fb
+}
+
+func (fb *flagBase[T]) NoNegation() FluentFlag[T] {
+	fb.noNegation = true
+	return fb
+}
+
+func (fb *flagBase[T]) Required() FluentFlag[T] {
+	fb.required = true
+	return fb
+}
+
+func (fb *flagBase[T]) Validate(validator func(T) error) FluentFlag[T] {
+	fb.validators = append(fb.validators, validator)
+	return fb
+}
+
+func (fb *flagBase[T]) Hidden() FluentFlag[T] {
+	fb.hide = true
+	return fb
+}
+
+func (fb *flagBase[T]) Section(name string) FluentFlag[T] {
+	fb.sectionName = name
+	return fb
+}
+
+func (fb *flagBase[T]) Complete(completer func(prefix string) []string) FluentFlag[T] {
+	fb.completeFunc = completer
 	return fb
 }
 
@@ -83,11 +219,63 @@ func (fb flagBase[T]) names() []string {
 func (fb flagBase[T]) docline() string {
 	return fb.docLine
 }
+func (fb flagBase[T]) persistent() bool {
+	return fb.persist
+}
+func (fb flagBase[T]) env() string {
+	return fb.envName
+}
+func (fb flagBase[T]) isSet() bool {
+	return fb.alreadySet
+}
+func (fb flagBase[T]) given() bool {
+	return fb.alreadySet
+}
+func (fb flagBase[T]) isRequired() bool {
+	return fb.required
+}
+func (fb flagBase[T]) hidden() bool {
+	return fb.hide
+}
+func (fb flagBase[T]) section() string {
+	return fb.sectionName
+}
+func (fb flagBase[T]) completer() func(string) []string {
+	return fb.completeFunc
+}
+func (fb *flagBase[T]) empty() {
+	var zero T
+	*fb.dest = zero
+	fb.alreadySet = true
+}
+func (flagBase[T]) check() error {
+	return nil // Values are validated when consumed.
+}
 
-func (fb *flagBase[T]) enforceDefault() {
-	if !fb.alreadySet {
-		*fb.dest = fb.def
+func (fb *flagBase[T]) enforceDefault() error {
+	if fb.alreadySet {
+		return nil
 	}
+
+	if fb.hasDefault {
+		if err := fb.validate(fb.def); err != nil {
+			return err
+		}
+	}
+
+	*fb.dest = fb.def
+	return nil
+}
+
+// validate calls the validators on a value, stopping at the first error.
+func (fb *flagBase[T]) validate(value T) error {
+	for _, validator := range fb.validators {
+		if err := validator(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 //////////////////////////
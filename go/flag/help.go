@@ -2,39 +2,236 @@ package flag
 
 import (
 	"fmt"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/mooss/bagend/go/fun/eager/lie"
 )
 
+// defaultHelpWidth is the width of the help page when the terminal width is unknown.
+const defaultHelpWidth = 80
+
+// WithHelpWidth wraps the help page to the given width instead of the terminal width, which is
+// read from the COLUMNS environment variable.
+func WithHelpWidth(width int) func(*Parser) {
+	return func(cfg *Parser) {
+		cfg.helpWidth = width
+	}
+}
+
 // Help returns a formatted help string showing all registered flags and their documentation.
 func (par *Parser) Help() string {
 	var builder strings.Builder
+	width := par.width()
 
 	///////////
 	// Usage //
 
-	builder.WriteString("Usage: ")
-	builder.WriteString(par.usage)
+	builder.WriteString(par.usageLine() + "\n")
+
+	///////////////
+	// Arguments //
+
+	if len(par.arguments) > 0 {
+		builder.WriteString("\nArguments:\n")
+		names := lie.Map(func(arg argument) string { return arg.names()[0] }, par.arguments)
+		writeTable(&builder, names, lie.Map(argument.docline, par.arguments), width)
+	}
 
 	///////////
 	// Flags //
 
-	builder.WriteString("\n\nFlags:\n")
+	// Flags without section come first, the others are grouped by section in order of appearance.
+	visible := par.shown()
+	builder.WriteString("\nFlags:\n")
+	par.writeFlags(&builder, inSection(visible, ""), width)
 
-	align := 0
-	mkdecl := func(flg flag) string {
-		res := strings.Join(lie.Map(name2flag, flg.names()), ", ")
-		align = max(align, len(res))
+	for _, section := range sections(visible) {
+		builder.WriteString("\n" + section + ":\n")
+		par.writeFlags(&builder, inSection(visible, section), width)
+	}
+
+	if inherited := par.shownInherited(); len(inherited) > 0 {
+		builder.WriteString("\nGlobal flags:\n")
+		par.writeFlags(&builder, inherited, width)
+	}
+
+	if len(par.constraints) > 0 {
+		builder.WriteString("\nConstraints:\n")
+		for _, cns := range par.constraints {
+			builder.WriteString("  " + cns.describe() + "\n")
+		}
+	}
+
+	//////////////
+	// Commands //
+
+	if len(par.commands) > 0 {
+		builder.WriteString("\nCommands:\n")
+		names := lie.Map(func(cmd *Parser) string { return cmd.name }, par.commands)
+		writeTable(&builder, names, lie.Map((*Parser).summary, par.commands), width)
+	}
+
+	return builder.String()
+}
+
+// usageLine returns the usage of the parser followed by the synopsis of its positional arguments.
+func (par *Parser) usageLine() string {
+	words := []string{par.usage}
+	for _, arg := range par.arguments {
+		words = append(words, arg.synopsis())
+	}
+
+	if par.passthrough {
+		words = append(words, "[-- ARGS...]")
+	}
+
+	res := "Usage:"
+	for _, word := range words {
+		if word != "" {
+			res += " " + word
+		}
+	}
+
+	return res
+}
+
+// writeFlags writes the declaration and documentation of the given flags, with proper alignment.
+func (par *Parser) writeFlags(builder *strings.Builder, flags []flag, width int) {
+	writeTable(builder, lie.Map(declaration, flags), lie.Map(par.documentation, flags), width)
+}
+
+// declaration returns the names of a flag as they must be written on the command line.
+func declaration(flg flag) string {
+	res := strings.Join(documentedNames(flg), ", ")
+	if placeholder := flg.placeholder(); placeholder != "" {
+		res += " " + placeholder
+	}
+
+	return res
+}
+
+// documentedNames returns the names of a flag as they must be written on the command line, the
+// negation being merged into the long canonical name, e.g. `--[no-]verbose`, or listed last when
+// the canonical name is a single letter, e.g. `-v, --no-v`.
+func documentedNames(flg flag) []string {
+	res := lie.Map(name2flag, flg.names())
+	if flg.negation() == "" {
 		return res
 	}
-	declarations := lie.Map(mkdecl, par.canonical)
 
-	// Format with proper alignment.
+	if canonical := flg.names()[0]; len(canonical) > 1 {
+		res[0] = "--[no-]" + canonical
+	} else {
+		res = append(res, "--no-"+canonical)
+	}
+
+	return res
+}
+
+// documentation returns the docline of a flag, completed by its requirement, default value and
+// environment variable.
+func (par *Parser) documentation(flg flag) string {
+	res := flg.docline()
+	if flg.isRequired() {
+		res += " (required)"
+	} else if def := flg.defaultValue(); def != "" {
+		res += " (default: " + def + ")"
+	}
+
+	if env := par.envVar(flg); env != "" {
+		res += " [env: " + env + "]"
+	}
+
+	return res
+}
+
+// width returns the width of the help page.
+func (par *Parser) width() int {
+	if par.helpWidth > 0 {
+		return par.helpWidth
+	}
+
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+
+	return defaultHelpWidth
+}
+
+///////////////
+// Utilities //
+
+// minWrapWidth is the minimal width of the right column of a table, below which it overflows the
+// page instead of becoming unreadable.
+const minWrapWidth = 20
+
+// writeTable writes two aligned columns, the right column being wrapped to fit in width with a
+// hanging indentation.
+func writeTable(builder *strings.Builder, left, right []string, width int) {
+	align := 0
+	for _, cell := range left {
+		align = max(align, utf8.RuneCountInString(cell))
+	}
+
+	indent := strings.Repeat(" ", align+4)
 	format := fmt.Sprintf("  %%-%ds  %%s\n", align)
-	for i, decl := range declarations {
-		builder.WriteString(fmt.Sprintf(format, decl, par.canonical[i].docline()))
+	for i, cell := range left {
+		lines := wrap(right[i], max(width-len(indent), minWrapWidth))
+		builder.WriteString(fmt.Sprintf(format, cell, strings.Join(lines, "\n"+indent)))
 	}
+}
 
-	return builder.String()
+// wrap splits text into lines of at most width runes, unless a single word is longer.
+func wrap(text string, width int) []string {
+	var (
+		res  []string
+		line string
+	)
+
+	for _, word := range strings.Fields(text) {
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			res = append(res, line)
+			line = word
+		}
+	}
+
+	return append(res, line)
+}
+
+// shown returns the flags of the parser that are not hidden.
+func (par *Parser) shown() []flag {
+	return slices.DeleteFunc(slices.Clone(par.canonical), flag.hidden)
+}
+
+// shownInherited returns the inherited flags that are not hidden.
+func (par *Parser) shownInherited() []flag {
+	return slices.DeleteFunc(par.inherited(), flag.hidden)
+}
+
+// sections returns the named sections of the given flags, in order of appearance.
+func sections(flags []flag) []string {
+	var res []string
+	for _, flg := range flags {
+		if flg.section() != "" && !slices.Contains(res, flg.section()) {
+			res = append(res, flg.section())
+		}
+	}
+
+	return res
+}
+
+// inSection returns the flags belonging to the given section.
+func inSection(flags []flag, section string) []flag {
+	return slices.DeleteFunc(slices.Clone(flags), func(flg flag) bool {
+		return flg.section() != section
+	})
 }
@@ -16,11 +16,11 @@ func TestParser_Help(t *testing.T) {
 				par.Int("intflag", new(int), "integer flag")
 				par.String("strflag", new(string), "string flag")
 			},
-			`Usage: 
+			`Usage:
 
 Flags:
-  --intflag  integer flag
-  --strflag  string flag
+  --intflag INT     integer flag
+  --strflag STRING  string flag
 `,
 		},
 		{
@@ -28,10 +28,10 @@ Flags:
 			func(par *Parser) {
 				par.Bool("boolflag", new(bool), "boolean flag").Alias("b", "bool")
 			},
-			`Usage: 
+			`Usage:
 
 Flags:
-  --boolflag, -b, --bool  boolean flag
+  --[no-]boolflag, -b, --bool  boolean flag
 `,
 		},
 		{
@@ -43,14 +43,89 @@ Flags:
 			`Usage: testprog [options]
 
 Flags:
-  --help, -h  Print this help page
-  --intflag   integer flag
+  --help, -h     Print this help page
+  --intflag INT  integer flag
+`,
+		},
+		{
+			"defaults and placeholders",
+			func(par *Parser) {
+				par.Int("jobs", new(int), "parallel jobs").Default(4)
+				par.IntSlice("sizes", new([]int), "block sizes").Default([]int{1, 2})
+				par.Bool("color", new(bool), "colored output").Default(true)
+			},
+			`Usage:
+
+Flags:
+  --jobs INT      parallel jobs (default: 4)
+  --sizes INT...  block sizes (default: 1,2)
+  --[no-]color    colored output (default: true)
+`,
+		},
+		{
+			"single-letter negation",
+			func(par *Parser) {
+				par.Bool("v", new(bool), "verbose output").Alias("verbose")
+			},
+			`Usage:
+
+Flags:
+  -v, --verbose, --no-v  verbose output
+`,
+		},
+		{
+			"hidden flags",
+			func(par *Parser) {
+				par.Int("visible", new(int), "shown")
+				par.Int("secret", new(int), "not shown").Hidden()
+			},
+			`Usage:
+
+Flags:
+  --visible INT  shown
+`,
+		},
+		{
+			"sections",
+			func(par *Parser) {
+				par.String("host", new(string), "host").Section("Network")
+				par.Bool("verbose", new(bool), "verbose output")
+				par.Int("retries", new(int), "retries").Section("Reliability")
+				par.Int("port", new(int), "port").Section("Network")
+			},
+			`Usage:
+
+Flags:
+  --[no-]verbose  verbose output
+
+Network:
+  --host STRING  host
+  --port INT     port
+
+Reliability:
+  --retries INT  retries
+`,
+		},
+		{
+			"wrapping",
+			func(par *Parser) {
+				WithHelpWidth(40)(par)
+				par.String("name", new(string), "a rather long documentation line that wraps").
+					Default("anonymous")
+			},
+			`Usage:
+
+Flags:
+  --name STRING  a rather long
+                 documentation line that
+                 wraps (default:
+                 anonymous)
 `,
 		},
 		{
 			"empty parser",
 			func(*Parser) {},
-			`Usage: 
+			`Usage:
 
 Flags:
 `,
@@ -68,3 +143,13 @@ Flags:
 		})
 	}
 }
+
+func TestParser_HelpWidth(t *testing.T) {
+	t.Setenv("COLUMNS", "30")
+	eq(t, 30, NewParser().width())
+	eq(t, 50, NewParser(WithHelpWidth(50)).width())
+	eq(t, 50, NewParser(WithHelpWidth(50)).Command("cmd", "").width())
+
+	t.Setenv("COLUMNS", "invalid")
+	eq(t, defaultHelpWidth, NewParser().width())
+}
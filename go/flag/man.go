@@ -0,0 +1,177 @@
+// This file generates man pages in the roff format of man(7).
+
+package flag
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/mooss/bagend/go/fun/eager/lie"
+)
+
+// ManPage holds the information of a man page that cannot be derived from the parser.
+type ManPage struct {
+	// Section is the manual section, defaulting to 1 (user commands).
+	Section int
+
+	// Description is the short description of the NAME section.
+	Description string
+
+	// Date, Source and Manual are the optional footer and header fields, e.g. `2024-01-31`,
+	// `tool 1.2.0` and `Tool Manual`.
+	Date, Source, Manual string
+
+	// ExitStatus describes the exit codes, defaulting to 0 for success and 1 for failure.
+	ExitStatus map[int]string
+}
+
+// WriteMan writes the man page of the program named by WithHelp to w.
+// The output only depends on the parser and the page, making it suitable for golden tests.
+func (par *Parser) WriteMan(w io.Writer, page ManPage) error {
+	if par.program == "" {
+		return errors.New("man page requires a program name, see WithHelp")
+	}
+
+	var builder strings.Builder
+	section := max(page.Section, 1)
+	fmt.Fprintf(&builder, ".TH %s %d %s %s %s\n",
+		roffQuote(strings.ToUpper(strings.ReplaceAll(par.program, " ", "-"))), section,
+		roffQuote(page.Date), roffQuote(page.Source), roffQuote(page.Manual))
+
+	builder.WriteString(".SH NAME\n" + roffEscape(par.program))
+	if page.Description != "" {
+		builder.WriteString(` \- ` + roffEscape(page.Description))
+	}
+
+	builder.WriteString("\n.SH SYNOPSIS\n")
+	par.writeManSynopsis(&builder)
+
+	if len(par.arguments) > 0 {
+		builder.WriteString(".SH ARGUMENTS\n")
+		par.writeManArguments(&builder)
+	}
+
+	builder.WriteString(".SH OPTIONS\n")
+	par.writeManFlags(&builder, ".SS")
+
+	if len(par.commands) > 0 {
+		builder.WriteString(".SH COMMANDS\n")
+		for _, cmd := range par.tree()[1:] {
+			builder.WriteString(".SS " + roffQuote(par.path(cmd)) + "\n")
+			cmd.writeManSynopsis(&builder)
+			cmd.writeManArguments(&builder)
+			cmd.writeManFlags(&builder, ".PP\n.B")
+		}
+	}
+
+	par.writeManEnvironment(&builder)
+
+	builder.WriteString(".SH EXIT STATUS\n")
+	status := page.ExitStatus
+	if status == nil {
+		status = map[int]string{0: "Success.", 1: "Failure, e.g. invalid arguments."}
+	}
+
+	for _, code := range slices.Sorted(maps.Keys(status)) {
+		fmt.Fprintf(&builder, ".TP\n.B %d\n%s\n", code, roffEscape(status[code]))
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// writeManSynopsis writes the usage line of the parser, with the program name in bold.
+func (par *Parser) writeManSynopsis(builder *strings.Builder) {
+	synopsis := strings.Fields(par.summary())
+	for _, arg := range par.arguments {
+		synopsis = append(synopsis, arg.synopsis())
+	}
+
+	if par.passthrough {
+		synopsis = append(synopsis, "[-- ARGS...]")
+	}
+
+	builder.WriteString(".B " + roffQuote(par.program) + "\n")
+	if len(synopsis) > 0 {
+		builder.WriteString(roffEscape(strings.Join(synopsis, " ")) + "\n")
+	}
+}
+
+// writeManArguments writes the documentation of the positional arguments of the parser.
+func (par *Parser) writeManArguments(builder *strings.Builder) {
+	for _, arg := range par.arguments {
+		fmt.Fprintf(builder, ".TP\n.I %s\n%s\n",
+			roffQuote(arg.names()[0]), roffEscape(arg.docline()))
+	}
+}
+
+// writeManFlags writes the documentation of the visible flags of the parser, the flags of named
+// sections being introduced by the heading macro.
+func (par *Parser) writeManFlags(builder *strings.Builder, heading string) {
+	visible := par.shown()
+	for _, flg := range inSection(visible, "") {
+		par.writeManFlag(builder, flg)
+	}
+
+	for _, section := range sections(visible) {
+		builder.WriteString(heading + " " + roffQuote(section) + "\n")
+		for _, flg := range inSection(visible, section) {
+			par.writeManFlag(builder, flg)
+		}
+	}
+}
+
+// writeManFlag writes the declaration of a flag in bold, followed by its documentation.
+func (par *Parser) writeManFlag(builder *strings.Builder, flg flag) {
+	names := lie.Map(func(name string) string {
+		return `\fB` + roffEscape(name) + `\fR`
+	}, documentedNames(flg))
+
+	declaration := strings.Join(names, ", ")
+	if placeholder := flg.placeholder(); placeholder != "" {
+		declaration += ` \fI` + roffEscape(placeholder) + `\fR`
+	}
+
+	fmt.Fprintf(builder, ".TP\n%s\n%s\n", declaration, roffEscape(par.documentation(flg)))
+}
+
+// writeManEnvironment writes the environment variables bound to the flags of the parser and its
+// subcommands.
+func (par *Parser) writeManEnvironment(builder *strings.Builder) {
+	var lines []string
+	for _, cmd := range par.tree() {
+		for _, flg := range cmd.shown() {
+			if env := cmd.envVar(flg); env != "" {
+				source := strings.TrimSpace(par.path(cmd) + " " + name2flag(flg.names()[0]))
+				lines = append(lines, fmt.Sprintf(".TP\n.B %s\n%s (%s)\n",
+					roffQuote(env), roffEscape(flg.docline()), roffEscape(source)))
+			}
+		}
+	}
+
+	if len(lines) > 0 {
+		builder.WriteString(".SH ENVIRONMENT\n" + strings.Join(lines, ""))
+	}
+}
+
+///////////////
+// Utilities //
+
+// roffEscape escapes the characters of a text line that are special in roff.
+func roffEscape(text string) string {
+	res := strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(text)
+	if strings.HasPrefix(res, ".") || strings.HasPrefix(res, "'") {
+		res = `\&` + res
+	}
+
+	return res
+}
+
+// roffQuote escapes and quotes a macro argument.
+func roffQuote(arg string) string {
+	return `"` + strings.ReplaceAll(roffEscape(arg), `"`, `\(dq`) + `"`
+}
@@ -0,0 +1,82 @@
+package flag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParser_WriteMan(t *testing.T) {
+	par := NewParser(WithHelp("tool", "[FLAGS]"), WithEnvPrefix("TOOL"))
+	Arg[String](par, "SRC", new(string), "source file")
+	par.Choice("format", new(string), []string{"json", "yaml"}, "output format").
+		Alias("f").Default("json")
+	par.Bool("color", new(bool), "colored output").Env("COLOR")
+	par.Int("retries", new(int), "number of retries").Section("Network")
+	par.Int("secret", new(int), "hidden flag").Hidden()
+	remote := par.Command("remote", "[FLAGS] NAME")
+	remote.String("host", new(string), "remote host").Required()
+
+	var out strings.Builder
+	noErr(t, par.WriteMan(&out, ManPage{Description: "do things", Date: "2024-01-31"}))
+	eq(t, `.TH "TOOL" 1 "2024\-01\-31" "" ""
+.SH NAME
+tool \- do things
+.SH SYNOPSIS
+.B "tool"
+[FLAGS] SRC
+.SH ARGUMENTS
+.TP
+.I "SRC"
+source file
+.SH OPTIONS
+.TP
+\fB\-\-help\fR, \fB\-h\fR
+Print this help page
+.TP
+\fB\-\-format\fR, \fB\-f\fR \fI{json,yaml}\fR
+output format (default: json) [env: TOOL_FORMAT]
+.TP
+\fB\-\-[no\-]color\fR
+colored output [env: COLOR]
+.SS "Network"
+.TP
+\fB\-\-retries\fR \fIINT\fR
+number of retries [env: TOOL_RETRIES]
+.SH COMMANDS
+.SS "remote"
+.B "tool remote"
+[FLAGS] NAME
+.TP
+\fB\-\-host\fR \fISTRING\fR
+remote host (required) [env: TOOL_REMOTE_HOST]
+.SH ENVIRONMENT
+.TP
+.B "TOOL_FORMAT"
+output format (\-\-format)
+.TP
+.B "COLOR"
+colored output (\-\-color)
+.TP
+.B "TOOL_RETRIES"
+number of retries (\-\-retries)
+.TP
+.B "TOOL_REMOTE_HOST"
+remote host (remote \-\-host)
+.SH EXIT STATUS
+.TP
+.B 0
+Success.
+.TP
+.B 1
+Failure, e.g. invalid arguments.
+`, out.String())
+
+	t.Run("escaping", func(t *testing.T) {
+		eq(t, `\&.hidden \e \-`, roffEscape(`.hidden \ -`))
+		eq(t, `"say \(dqhi\(dq"`, roffQuote(`say "hi"`))
+	})
+
+	t.Run("missing program name", func(t *testing.T) {
+		yesErr(t, NewParser().WriteMan(&out, ManPage{}))
+	})
+}
@@ -0,0 +1,145 @@
+// This file implements flags storing key-value pairs, e.g. `--label env=prod --label team=core`.
+
+package flag
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// mapFlag represents a flag that can consume multiple key-value pairs.
+// It implements both the flag and FluentFlag interfaces.
+type mapFlag[K comparable, V any, KD Decoder[K], VD Decoder[V]] struct {
+	// flagBase implements the FluentFlag interface and part of the flag interface.
+	flagBase[map[K]V]
+	keyDecoder   KD
+	valueDecoder VD
+	options      MapOptions
+}
+
+// MapOptions configures the syntax of map flags.
+type MapOptions struct {
+	// Separator separates a key from its value, defaulting to `=`.
+	Separator string
+
+	// Multi allows multiple comma-separated pairs in one argument, e.g. `env=prod,team=core`.
+	Multi bool
+
+	// Unique makes duplicate keys an error, otherwise the last value wins.
+	Unique bool
+}
+
+///////////////////////////////////////////
+// Rest of flag interface implementation //
+
+func (mf *mapFlag[K, V, KD, VD]) consume(value string) error {
+	pairs := []string{value}
+	if mf.options.Multi {
+		pairs = strings.Split(value, ",")
+	}
+
+	// Values consumed during this parse replace the default instead of being added to it.
+	res := map[K]V{}
+	if mf.alreadySet {
+		res = maps.Clone(*mf.dest)
+	}
+
+	for _, pair := range pairs {
+		rawKey, rawValue, found := strings.Cut(pair, mf.separator())
+		if !found {
+			return fmt.Errorf("expected %s, got %q", mf.placeholder(), pair)
+		}
+
+		key, err := mf.keyDecoder.Decode(rawKey)
+		if err != nil {
+			return fmt.Errorf("key %q: %w", rawKey, err)
+		}
+
+		if _, exists := res[key]; exists && mf.options.Unique {
+			return fmt.Errorf("duplicate key %q", rawKey)
+		}
+
+		res[key], err = mf.valueDecoder.Decode(rawValue)
+		if err != nil {
+			return fmt.Errorf("value of key %q: %w", rawKey, err)
+		}
+	}
+
+	// The complete map is validated by check.
+	*mf.dest = res
+	mf.alreadySet = true
+
+	return nil
+}
+
+func (mf *mapFlag[K, V, KD, VD]) check() error {
+	if !mf.alreadySet {
+		return nil
+	}
+
+	return mf.validate(*mf.dest)
+}
+
+func (*mapFlag[K, V, KD, VD]) arity() int {
+	return -1 // A map can always consume more pairs.
+}
+
+func (*mapFlag[K, V, KD, VD]) implicit() string {
+	return ""
+}
+
+func (*mapFlag[K, V, KD, VD]) negation() string {
+	return ""
+}
+
+func (mf *mapFlag[K, V, KD, VD]) placeholder() string {
+	return "KEY" + mf.separator() + "VALUE"
+}
+
+func (*mapFlag[K, V, KD, VD]) choices() []string {
+	return nil
+}
+
+func (*mapFlag[K, V, KD, VD]) decodes(string) bool {
+	return false // Pairs are never confused with negative values.
+}
+
+func (*mapFlag[K, V, KD, VD]) files() fileCompletion {
+	return noFiles
+}
+
+func (mf *mapFlag[K, V, KD, VD]) defaultValue() string {
+	if !mf.hasDefault {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(mf.def))
+	for key, value := range mf.def {
+		pairs = append(pairs,
+			formatValue(mf.keyDecoder, key)+mf.separator()+formatValue(mf.valueDecoder, value))
+	}
+
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (*mapFlag[K, V, KD, VD]) kind() string {
+	var (
+		key   K
+		value V
+	)
+	return fmt.Sprintf("map of %T to %T", key, value)
+}
+
+///////////////
+// Utilities //
+
+func (mf *mapFlag[K, V, KD, VD]) separator() string {
+	if mf.options.Separator == "" {
+		return "="
+	}
+
+	return mf.options.Separator
+}
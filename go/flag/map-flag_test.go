@@ -0,0 +1,79 @@
+package flag
+
+import "testing"
+
+func TestParser_MapFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  MapOptions
+		args     []string
+		expected map[string]int
+		valid    bool
+	}{
+		{"default", MapOptions{}, nil, map[string]int{"a": 0}, true},
+		{"pairs", MapOptions{}, []string{"--label", "a=1", "--label", "b=2"},
+			map[string]int{"a": 1, "b": 2}, true},
+		{"consecutive pairs", MapOptions{}, []string{"--label", "a=1", "b=2"},
+			map[string]int{"a": 1, "b": 2}, true},
+		{"last wins", MapOptions{}, []string{"--label=a=1", "--label", "a=2"},
+			map[string]int{"a": 2}, true},
+		{"unique", MapOptions{Unique: true}, []string{"--label", "a=1", "--label", "a=2"},
+			nil, false},
+		{"separator", MapOptions{Separator: ":"}, []string{"--label", "a:1"},
+			map[string]int{"a": 1}, true},
+		{"multi", MapOptions{Multi: true}, []string{"--label", "a=1,b=2", "--label", "c=3"},
+			map[string]int{"a": 1, "b": 2, "c": 3}, true},
+		{"missing separator", MapOptions{}, []string{"--label", "a"}, nil, false},
+		{"invalid value", MapOptions{}, []string{"--label", "a=b"}, nil, false},
+		{"commas without multi", MapOptions{}, []string{"--label", "a=1,b=2"}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var labels map[string]int
+			par := NewParser()
+			RegisterMap[String, Int](par, "label", &labels, "labels", tt.options).
+				Default(map[string]int{"a": 0})
+
+			err := par.Parse(tt.args)
+			if !tt.valid {
+				yesErr(t, err)
+				return
+			}
+
+			noErr(t, err)
+			eq(t, tt.expected, labels)
+		})
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		var labels map[string]string
+		setup := func() *Parser {
+			par := NewParser()
+			par.StringMap("label", &labels, "labels").Validate(MinKeys[map[string]string](2))
+			return par
+		}
+
+		noErr(t, setup().Parse([]string{"--label", "a=1", "--label", "b=2"}))
+		eq(t, map[string]string{"a": "1", "b": "2"}, labels)
+		err := setup().Parse([]string{"--label", "a=1"})
+		yesErr(t, err)
+		if err != nil {
+			eq(t, "when consuming label (map of string to string): length 1 is less than 2",
+				err.Error())
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		par := NewParser()
+		par.StringMap("label", new(map[string]string), "labels")
+		RegisterMap[String, Int](par, "limit", new(map[string]int), "limits",
+			MapOptions{Separator: ":"})
+		eq(t, `Usage:
+
+Flags:
+  --label KEY=VALUE  labels
+  --limit KEY:VALUE  limits
+`, par.Help())
+	})
+}
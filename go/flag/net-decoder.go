@@ -0,0 +1,168 @@
+// This file implements the network-related decoders.
+
+package flag
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/////////
+// URL //
+
+// URL implements Decoder[*url.URL], Placeholder and Formatter[*url.URL] for absolute URLs.
+type URL struct {
+	// Schemes are the accepted schemes, all schemes are accepted when empty.
+	Schemes []string
+
+	// Hostless accepts URLs without host, e.g. `mailto:user@example.com` or `file:///tmp`.
+	// Otherwise, a missing host is an error, catching mistakes like `localhost:8080`.
+	Hostless bool
+}
+
+func (dec URL) Decode(source string) (*url.URL, error) {
+	res, err := url.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Scheme == "" {
+		return nil, fmt.Errorf("url %q has no scheme", source)
+	}
+
+	if res.Host == "" && !dec.Hostless {
+		return nil, fmt.Errorf("url %q has no host", source)
+	}
+
+	if len(dec.Schemes) > 0 && !slices.Contains(dec.Schemes, strings.ToLower(res.Scheme)) {
+		return nil, fmt.Errorf("url scheme %q is not one of %s",
+			res.Scheme, strings.Join(dec.Schemes, ", "))
+	}
+
+	return res, nil
+}
+
+func (URL) Placeholder() string { return "URL" }
+
+func (URL) Format(value *url.URL) string {
+	if value == nil {
+		return ""
+	}
+
+	return value.String()
+}
+
+///////////////////
+// Host and port //
+
+// AddrPort implements Decoder[netip.AddrPort], Placeholder and Formatter[netip.AddrPort].
+type AddrPort struct {
+	// DefaultPort is used when the port is omitted, the port is mandatory when it is 0.
+	DefaultPort uint16
+}
+
+func (dec AddrPort) Decode(source string) (netip.AddrPort, error) {
+	res, err := netip.ParseAddrPort(source)
+	if err == nil || dec.DefaultPort == 0 {
+		return res, err
+	}
+
+	addr, addrErr := netip.ParseAddr(strings.TrimSuffix(strings.TrimPrefix(source, "["), "]"))
+	if addrErr != nil {
+		return netip.AddrPort{}, err
+	}
+
+	return netip.AddrPortFrom(addr, dec.DefaultPort), nil
+}
+
+func (AddrPort) Placeholder() string { return "IP:PORT" }
+
+func (AddrPort) Format(value netip.AddrPort) string {
+	return value.String()
+}
+
+// HostPort implements Decoder[string], Placeholder and Formatter[string] for `host:port` pairs
+// where host can be a name or an IP address.
+// The decoded value is normalized with net.JoinHostPort.
+type HostPort struct {
+	// DefaultPort is used when the port is omitted, the port is mandatory when it is 0.
+	DefaultPort uint16
+}
+
+func (dec HostPort) Decode(source string) (string, error) {
+	host, port, err := net.SplitHostPort(source)
+	if err != nil {
+		if dec.DefaultPort == 0 || strings.Count(source, ":") == 1 {
+			return "", err
+		}
+
+		host, port = strings.TrimSuffix(strings.TrimPrefix(source, "["), "]"), ""
+	}
+
+	if port == "" && dec.DefaultPort != 0 {
+		port = strconv.Itoa(int(dec.DefaultPort))
+	}
+
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return "", fmt.Errorf("invalid port %q", port)
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+func (HostPort) Placeholder() string { return "HOST:PORT" }
+
+func (HostPort) Format(value string) string {
+	return value
+}
+
+//////////////////
+// IP addresses //
+
+// Addr implements Decoder[netip.Addr], Placeholder and Formatter[netip.Addr].
+type Addr struct{}
+
+func (Addr) Decode(source string) (netip.Addr, error) {
+	return netip.ParseAddr(source)
+}
+
+func (Addr) Placeholder() string { return "IP" }
+
+func (Addr) Format(value netip.Addr) string {
+	return value.String()
+}
+
+// Prefix implements Decoder[netip.Prefix], Placeholder and Formatter[netip.Prefix] for CIDR
+// notations.
+type Prefix struct{}
+
+func (Prefix) Decode(source string) (netip.Prefix, error) {
+	return netip.ParsePrefix(source)
+}
+
+func (Prefix) Placeholder() string { return "CIDR" }
+
+func (Prefix) Format(value netip.Prefix) string {
+	return value.String()
+}
+
+/////////
+// MAC //
+
+// MAC implements Decoder[net.HardwareAddr], Placeholder and Formatter[net.HardwareAddr].
+type MAC struct{}
+
+func (MAC) Decode(source string) (net.HardwareAddr, error) {
+	return net.ParseMAC(source)
+}
+
+func (MAC) Placeholder() string { return "MAC" }
+
+func (MAC) Format(value net.HardwareAddr) string {
+	return value.String()
+}
@@ -0,0 +1,95 @@
+package flag
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"testing"
+)
+
+func TestURL(t *testing.T) {
+	got, err := URL{}.Decode("https://example.com:8443/path?q=1")
+	noErr(t, err)
+	eq(t, "example.com:8443", got.Host)
+
+	web := URL{Schemes: []string{"http", "https"}}
+	_, err = web.Decode("HTTPS://example.com")
+	noErr(t, err)
+	yesErr(t, second(web.Decode("ftp://example.com")))
+	yesErr(t, second(URL{}.Decode("example.com")))
+	yesErr(t, second(URL{}.Decode("http://[::1")))
+	yesErr(t, second(URL{}.Decode("localhost:8080")))
+	yesErr(t, second(URL{}.Decode("mailto:x")))
+
+	got, err = URL{Hostless: true}.Decode("mailto:x")
+	noErr(t, err)
+	eq(t, "x", got.Opaque)
+
+	eq(t, "https://example.com", URL{}.Format(&url.URL{Scheme: "https", Host: "example.com"}))
+	eq(t, "", URL{}.Format(nil))
+
+	par := NewParser()
+	par.URL("proxy", new(*url.URL), "proxy").Default(nil)
+	eq(t, `Usage:
+
+Flags:
+  --proxy URL  proxy
+`, par.Help())
+}
+
+func TestAddrPort(t *testing.T) {
+	decodeExpect(t, AddrPort{}, "127.0.0.1:80", netip.MustParseAddrPort("127.0.0.1:80"))
+	decodeExpect(t, AddrPort{DefaultPort: 53}, "10.0.0.1", netip.MustParseAddrPort("10.0.0.1:53"))
+	decodeExpect(t, AddrPort{DefaultPort: 53}, "[::1]", netip.MustParseAddrPort("[::1]:53"))
+	decodeExpect(t, AddrPort{DefaultPort: 53}, "::1", netip.MustParseAddrPort("[::1]:53"))
+	yesErr(t, second(AddrPort{}.Decode("10.0.0.1")))
+	yesErr(t, second(AddrPort{DefaultPort: 53}.Decode("localhost")))
+}
+
+func TestHostPort(t *testing.T) {
+	decodeExpect(t, HostPort{}, "example.com:80", "example.com:80")
+	decodeExpect(t, HostPort{DefaultPort: 443}, "example.com", "example.com:443")
+	decodeExpect(t, HostPort{DefaultPort: 443}, "example.com:", "example.com:443")
+	decodeExpect(t, HostPort{DefaultPort: 443}, "[::1]", "[::1]:443")
+	decodeExpect(t, HostPort{}, "[::1]:8080", "[::1]:8080")
+	yesErr(t, second(HostPort{}.Decode("example.com")))
+	yesErr(t, second(HostPort{}.Decode("example.com:http")))
+	yesErr(t, second(HostPort{}.Decode("example.com:65536")))
+}
+
+func TestAddressDecoders(t *testing.T) {
+	decodeExpect(t, Addr{}, "192.168.1.1", netip.MustParseAddr("192.168.1.1"))
+	decodeExpect(t, Addr{}, "fe80::1", netip.MustParseAddr("fe80::1"))
+	yesErr(t, second(Addr{}.Decode("192.168.1")))
+
+	decodeExpect(t, Prefix{}, "10.0.0.0/8", netip.MustParsePrefix("10.0.0.0/8"))
+	yesErr(t, second(Prefix{}.Decode("10.0.0.0")))
+
+	mac, _ := net.ParseMAC("00:1a:2b:3c:4d:5e")
+	decodeExpect(t, MAC{}, "00-1A-2B-3C-4D-5E", mac)
+	yesErr(t, second(MAC{}.Decode("00:1a:2b")))
+}
+
+func TestParser_NetworkFlags(t *testing.T) {
+	var (
+		allow  []netip.Prefix
+		listen netip.AddrPort
+	)
+
+	par := NewParser()
+	par.PrefixSlice("allow", &allow, "allowed networks")
+	RegisterWith(par, AddrPort{DefaultPort: 8080}, "listen", &listen, "listen address").
+		Default(netip.MustParseAddrPort("0.0.0.0:8080"))
+	noErr(t, par.Parse([]string{"--allow", "10.0.0.0/8", "--allow", "192.168.0.0/16"}))
+	eq(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.0.0/16"),
+	}, allow)
+	eq(t, netip.MustParseAddrPort("0.0.0.0:8080"), listen)
+
+	eq(t, `Usage:
+
+Flags:
+  --allow CIDR...   allowed networks
+  --listen IP:PORT  listen address (default: 0.0.0.0:8080)
+`, par.Help())
+}
@@ -0,0 +1,194 @@
+// This file implements the numeric decoders.
+//
+// By default, numbers are decimal.
+// Setting the Literal field of a decoder accepts Go number literals instead, with base prefixes
+// (0x, 0o, 0b) and underscores between digits.
+// The convenience methods of the parser use decimal decoders, literal flags are registered by
+// giving the decoder explicitly, e.g. `RegisterWith(par, Int64{Literal: true}, "mask", &mask, "")`
+// or `RegisterSliceWith(par, Uint8{Literal: true}, "bytes", &bytes, "")`.
+
+package flag
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// OverflowError is returned when a number does not fit in the type it is decoded into.
+type OverflowError struct {
+	Value string // The number being decoded.
+	Type  string // The name of the destination type.
+}
+
+func (oe *OverflowError) Error() string {
+	return fmt.Sprintf("%s overflows %s", oe.Value, oe.Type)
+}
+
+func (*OverflowError) Unwrap() error {
+	return strconv.ErrRange
+}
+
+/////////////////////
+// Signed integers //
+
+// Int implements Decoder[int] and Placeholder.
+type Int struct{ Literal bool }
+
+func (dec Int) Decode(source string) (int, error) {
+	return decodeSigned[int](source, strconv.IntSize, dec.Literal)
+}
+
+func (Int) Placeholder() string { return "INT" }
+
+// Int8 implements Decoder[int8] and Placeholder.
+type Int8 struct{ Literal bool }
+
+func (dec Int8) Decode(source string) (int8, error) {
+	return decodeSigned[int8](source, 8, dec.Literal)
+}
+
+func (Int8) Placeholder() string { return "INT" }
+
+// Int16 implements Decoder[int16] and Placeholder.
+type Int16 struct{ Literal bool }
+
+func (dec Int16) Decode(source string) (int16, error) {
+	return decodeSigned[int16](source, 16, dec.Literal)
+}
+
+func (Int16) Placeholder() string { return "INT" }
+
+// Int32 implements Decoder[int32] and Placeholder.
+type Int32 struct{ Literal bool }
+
+func (dec Int32) Decode(source string) (int32, error) {
+	return decodeSigned[int32](source, 32, dec.Literal)
+}
+
+func (Int32) Placeholder() string { return "INT" }
+
+// Int64 implements Decoder[int64] and Placeholder.
+type Int64 struct{ Literal bool }
+
+func (dec Int64) Decode(source string) (int64, error) {
+	return decodeSigned[int64](source, 64, dec.Literal)
+}
+
+func (Int64) Placeholder() string { return "INT" }
+
+///////////////////////
+// Unsigned integers //
+
+// Uint implements Decoder[uint] and Placeholder.
+type Uint struct{ Literal bool }
+
+func (dec Uint) Decode(source string) (uint, error) {
+	return decodeUnsigned[uint](source, strconv.IntSize, dec.Literal)
+}
+
+func (Uint) Placeholder() string { return "UINT" }
+
+// Uint8 implements Decoder[uint8] and Placeholder.
+type Uint8 struct{ Literal bool }
+
+func (dec Uint8) Decode(source string) (uint8, error) {
+	return decodeUnsigned[uint8](source, 8, dec.Literal)
+}
+
+func (Uint8) Placeholder() string { return "UINT" }
+
+// Uint16 implements Decoder[uint16] and Placeholder.
+type Uint16 struct{ Literal bool }
+
+func (dec Uint16) Decode(source string) (uint16, error) {
+	return decodeUnsigned[uint16](source, 16, dec.Literal)
+}
+
+func (Uint16) Placeholder() string { return "UINT" }
+
+// Uint32 implements Decoder[uint32] and Placeholder.
+type Uint32 struct{ Literal bool }
+
+func (dec Uint32) Decode(source string) (uint32, error) {
+	return decodeUnsigned[uint32](source, 32, dec.Literal)
+}
+
+func (Uint32) Placeholder() string { return "UINT" }
+
+// Uint64 implements Decoder[uint64] and Placeholder.
+type Uint64 struct{ Literal bool }
+
+func (dec Uint64) Decode(source string) (uint64, error) {
+	return decodeUnsigned[uint64](source, 64, dec.Literal)
+}
+
+func (Uint64) Placeholder() string { return "UINT" }
+
+/////////////////////
+// Floating points //
+
+// Float32 implements Decoder[float32] and Placeholder.
+type Float32 struct{ Literal bool }
+
+func (dec Float32) Decode(source string) (float32, error) {
+	return decodeFloat[float32](source, 32, dec.Literal)
+}
+
+func (Float32) Placeholder() string { return "FLOAT" }
+
+// Float64 implements Decoder[float64] and Placeholder.
+type Float64 struct{ Literal bool }
+
+func (dec Float64) Decode(source string) (float64, error) {
+	return decodeFloat[float64](source, 64, dec.Literal)
+}
+
+func (Float64) Placeholder() string { return "FLOAT" }
+
+///////////////
+// Utilities //
+
+func decodeSigned[T int | int8 | int16 | int32 | int64](
+	source string, bits int, literal bool,
+) (T, error) {
+	value, err := strconv.ParseInt(source, base(literal), bits)
+	return T(value), overflow[T](source, err)
+}
+
+func decodeUnsigned[T uint | uint8 | uint16 | uint32 | uint64](
+	source string, bits int, literal bool,
+) (T, error) {
+	value, err := strconv.ParseUint(source, base(literal), bits)
+	return T(value), overflow[T](source, err)
+}
+
+func decodeFloat[T float32 | float64](source string, bits int, literal bool) (T, error) {
+	// Unlike integer parsing, float parsing always accepts underscores and hexadecimal numbers.
+	if !literal && (strings.Contains(source, "_") || strings.ContainsAny(source, "xX")) {
+		return 0, &strconv.NumError{Func: "ParseFloat", Num: source, Err: strconv.ErrSyntax}
+	}
+
+	value, err := strconv.ParseFloat(source, bits)
+	return T(value), overflow[T](source, err)
+}
+
+// base returns the base to give to strconv, 0 meaning that the base is given by the prefix.
+func base(literal bool) int {
+	if literal {
+		return 0
+	}
+
+	return 10
+}
+
+// overflow converts range errors into overflow errors.
+func overflow[T any](source string, err error) error {
+	if errors.Is(err, strconv.ErrRange) {
+		var zero T
+		return &OverflowError{Value: source, Type: fmt.Sprintf("%T", zero)}
+	}
+
+	return err
+}
@@ -5,7 +5,10 @@ package flag
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -16,10 +19,39 @@ type Parser struct {
 	flags         flagset
 	canonical     []flag
 	flagDefErrors []error
+	constraints   []constraint
 	Positional    PositionalArguments
-
-	printHelp bool
-	usage     string
+	arguments     []argument // Typed positional arguments, Positional is used when there are none.
+	Passthrough   []string   // Arguments following `--` when WithPassthrough is enabled.
+
+	printHelp   bool
+	usage       string
+	help        *bool // Destination of the help flag, shared with the subcommands.
+	helpWidth   int   // Width of the help page, 0 to use the terminal width.
+	envPrefix   string
+	builtins    []flag // Flags registered by the options, e.g. the help flag.
+	bundling    bool
+	passthrough bool
+
+	negativeNumbers bool
+
+	binders map[reflect.Type]binder // Decoders of the fields bound by Bind, see BindDecoder.
+
+	// Configuration file.
+	configPath    *string // Destination of the config flag.
+	lenientConfig bool
+	config        map[flag]configValue
+
+	// Subcommands.
+	name     string    // Name of the command, empty for the root parser.
+	program  string    // Invocation prefix, e.g. `tool remote add`.
+	parent   *Parser   // Parser this command was registered to, nil for the root parser.
+	commands []*Parser // Registered subcommands.
+	selected *Parser   // Subcommand selected during the last parse.
+
+	// Side effects of the help page.
+	output io.Writer
+	exit   func(int)
 }
 
 type ParserOpt func(*Parser)
@@ -28,13 +60,42 @@ type ParserOpt func(*Parser)
 // enabled.
 func WithHelp(arg0, usage string) func(*Parser) {
 	return func(cfg *Parser) {
-		cfg.usage = arg0 + " " + usage
-		cfg.Bool("help", &cfg.printHelp, "Print this help page").Alias("h")
+		cfg.program = arg0
+		cfg.usage = strings.TrimSpace(arg0 + " " + usage)
+		cfg.help = &cfg.printHelp
+		help := cfg.Bool("help", cfg.help, "Print this help page")
+		help.Alias("h").Persistent().NoNegation()
+		cfg.builtins = append(cfg.builtins, help.(flag))
+	}
+}
+
+// WithShortBundling allows to bundle short flags in a single argument, e.g. `-xvf file` instead
+// of `-x -v -f file`.
+func WithShortBundling() func(*Parser) {
+	return func(cfg *Parser) {
+		cfg.bundling = true
+	}
+}
+
+// WithPassthrough stores the arguments following `--` in Passthrough instead of treating them as
+// positional arguments, so that they can be forwarded untouched to another program.
+func WithPassthrough() func(*Parser) {
+	return func(cfg *Parser) {
+		cfg.passthrough = true
+	}
+}
+
+// WithNegativeNumbers treats negative numbers as values even when no flag is waiting for a value,
+// making them valid positional arguments.
+// It has no effect when a flag name starts with a digit (e.g. `-5`).
+func WithNegativeNumbers() func(*Parser) {
+	return func(cfg *Parser) {
+		cfg.negativeNumbers = true
 	}
 }
 
 func NewParser(opts ...ParserOpt) *Parser {
-	res := Parser{flags: flagset{}}
+	res := Parser{flags: flagset{}, output: os.Stdout, exit: os.Exit}
 	for _, opt := range opts {
 		opt(&res)
 	}
@@ -44,24 +105,54 @@ func NewParser(opts ...ParserOpt) *Parser {
 
 // Parse parses the given arguments.
 // It can be called multiple times.
+// When the first argument is `__complete`, the completion candidates are printed instead, see
+// complete.
 func (par *Parser) Parse(arguments []string) error {
+	if len(arguments) > 0 && arguments[0] == completeCommand {
+		return par.complete(arguments[1:])
+	}
+
+	defer par.clearConfig() // The loaded configuration only applies to one parse.
+
+	if err := par.parse(arguments, flagset{}); err != nil {
+		return err
+	}
+
+	if err := par.loadConfigFlag(); err != nil {
+		return err
+	}
+
+	return par.finalizeParse()
+}
+
+// parse processes the arguments with the flags of the parser and the inherited flags.
+// When a subcommand is selected, it is in charge of parsing the rest of the arguments.
+func (par *Parser) parse(arguments []string, inherited flagset) error {
+	par.selected = nil
+
 	expanded, err := par.validateAndExpand()
 	if err != nil {
 		return err
 	}
 
-	if err := par.processArguments(arguments, expanded); err != nil {
+	if err := par.prepareArguments(); err != nil {
 		return err
 	}
 
-	return par.finalizeParse()
+	for flagname, flg := range inherited {
+		if err := expanded.add(flagname, flg); err != nil {
+			return fmt.Errorf("command %s: persistent flag conflict: %w", par.name, err)
+		}
+	}
+
+	return par.processArguments(arguments, expanded)
 }
 
 // validateAndExpand checks definitions and expands the flags aliases.
 func (par *Parser) validateAndExpand() (flagset, error) {
-	if len(par.flagDefErrors) > 0 {
-		msg := fmt.Errorf("%d flag definition errors, refusing to parse", len(par.flagDefErrors))
-		return nil, errors.Join(append([]error{msg}, par.flagDefErrors...)...)
+	if defErrors := par.definitionErrors(); len(defErrors) > 0 {
+		msg := fmt.Errorf("%d flag definition errors, refusing to parse", len(defErrors))
+		return nil, errors.Join(append([]error{msg}, defErrors...)...)
 	}
 
 	expanded, errs := par.flags.expand()
@@ -74,20 +165,50 @@ func (par *Parser) validateAndExpand() (flagset, error) {
 }
 
 // processArguments loops over all the arguments and fills the given flagset.
+// The arguments following `--` are never interpreted as flags.
 //
 //nolint:revive // Can't easily lower cognitive complexity.
 func (par *Parser) processArguments(arguments []string, flags flagset) error {
 	var dest sink = &par.Positional
 	remaining := -1
+	dispatch := len(par.commands) > 0 // Only the first positional argument can select a command.
+	negatives := par.negativeNumbers && !flags.numeric()
+
+	var rest []string
+
+	for i, arg := range arguments {
+		if arg == "--" { // End of flags.
+			rest = arguments[i+1:]
+			break
+		}
+
+		// Negative values are values when they are not flags and either the waiting flag can decode
+		// them (e.g. `--since -3d`) or there is no risk of confusing them with a flag.
+		waiting := remaining > 0 || remaining < 0 && dest != &par.Positional
+		negative := isNegative(arg) && flags[arg] == nil &&
+			(waiting && decodes(dest, arg) || (waiting || negatives) && isNegativeNumber(arg))
 
-	for _, arg := range arguments {
-		if !strings.HasPrefix(arg, "-") { // Value.
+		if !strings.HasPrefix(arg, "-") || negative { // Value.
 			if remaining == 0 {
 				dest = &par.Positional
 			}
 
-			if err := dest.consume(arg); err != nil {
-				return fmt.Errorf("when consuming %s (%s): %w", dest.names()[0], dest.kind(), err)
+			if dispatch && dest == &par.Positional {
+				if cmd := par.command(arg); cmd != nil {
+					par.selected = cmd
+					return cmd.parse(arguments[i+1:], flags.persistent())
+				}
+
+				if arg == "help" && par.help != nil { // `tool help verb` is `tool verb --help`.
+					*par.help = true
+					continue
+				}
+
+				dispatch = false
+			}
+
+			if err := par.consumeValue(dest, arg); err != nil {
+				return err
 			}
 
 			remaining--
@@ -95,15 +216,28 @@ func (par *Parser) processArguments(arguments []string, flags flagset) error {
 		}
 
 		// Flag.
-		dest = flags[arg]
-		switch {
-		case dest == nil:
-			return fmt.Errorf("unknown flag: %s", arg)
-		case is[*singletonflag[bool, Bool]](dest):
-			dest.consume("true") //nolint:errcheck // Cannot fail.
+		uses, err := flags.resolve(arg, par.bundling)
+		if err != nil {
+			return err
+		}
+
+		for _, use := range uses {
+			dest = use.flg
+			remaining = use.flg.arity()
+
+			if !use.attached && remaining != 0 {
+				continue
+			}
+
+			if !use.attached {
+				use.value = use.flg.implicit()
+			}
+
+			if err := consumeArg(use.flg, use.value); err != nil {
+				return err
+			}
+
 			remaining = 0
-		default:
-			remaining = dest.arity()
 		}
 	}
 
@@ -111,21 +245,68 @@ func (par *Parser) processArguments(arguments []string, flags flagset) error {
 		return fmt.Errorf("flag `%s` requires a value", name2flag(dest.names()[0]))
 	}
 
+	if par.passthrough {
+		par.Passthrough = append([]string(nil), rest...)
+		return nil
+	}
+
+	for _, arg := range rest {
+		if err := par.consumePositional(arg); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-// finalizeParse handles the help page and enforce default values.
+// finalizeParse handles the help page and fills the unset flags from the environment, from the
+// configuration or from their default value.
+// Missing required flags and arguments and violated constraints are reported together.
+// The help page is the one of the last selected subcommand, and the unset flags are filled for
+// every parser on the command path.
 func (par *Parser) finalizeParse() error {
-	if par.printHelp {
-		fmt.Print(par.Help())
-		os.Exit(0)
+	if par.help != nil && *par.help {
+		fmt.Fprint(par.output, par.leaf().Help())
+		par.exit(0)
+		return nil
 	}
 
-	for _, flg := range par.canonical {
-		flg.enforceDefault()
+	var errs []error
+
+	for cmd := par; cmd != nil; cmd = cmd.selected {
+		for _, flg := range cmd.canonical {
+			if !flg.isSet() {
+				if err := cmd.consumeEnv(flg); err != nil {
+					return err
+				}
+			}
+
+			if !flg.isSet() {
+				if err := cmd.consumeConfig(flg); err != nil {
+					return err
+				}
+			}
+
+			if err := flg.check(); err != nil {
+				errs = append(errs, consumeError(flg, err))
+			}
+
+			if flg.isRequired() && !flg.isSet() {
+				errs = append(errs,
+					fmt.Errorf("missing required flag %s", name2flag(flg.names()[0])))
+			}
+
+			if err := flg.enforceDefault(); err != nil {
+				errs = append(errs, fmt.Errorf("when enforcing the default of %s (%s): %w",
+					flg.names()[0], flg.kind(), err))
+			}
+		}
+
+		errs = append(errs, cmd.finalizeArguments()...)
+		errs = append(errs, cmd.checkConstraints()...)
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 /////////////
@@ -156,18 +337,155 @@ func (fs flagset) expand() (flagset, []error) {
 	res := flagset{}
 
 	for canonical, flg := range fs {
-		res[canonical] = flg
+		// Canonical names are unique, but they may conflict with generated names.
+		if err := res.add(canonical, flg); err != nil {
+			errs = append(errs, err)
+		}
 
 		for _, alias := range flg.names()[1:] {
 			if err := res.add(name2flag(alias), flg); err != nil {
 				errs = append(errs, err)
 			}
 		}
+
+		if flg.negation() != "" {
+			if err := res.add("--no-"+flg.names()[0], negation{flg}); err != nil {
+				errs = append(errs, err)
+			}
+		}
 	}
 
 	return res, errs
 }
 
+// negation is the `--no-` form of a negatable flag.
+type negation struct {
+	flag
+}
+
+func (neg negation) implicit() string {
+	return neg.negation()
+}
+
+func (neg negation) consume(value string) error {
+	if value != neg.negation() {
+		return fmt.Errorf("negated flag does not accept a value")
+	}
+
+	return neg.flag.consume(value)
+}
+
+// flagUse is a flag designated by an argument, along with the value attached to it.
+type flagUse struct {
+	flg      flag
+	value    string
+	attached bool
+}
+
+// resolve returns the flags designated by an argument.
+// Only the last flag can require a value that is not attached to it.
+func (fs flagset) resolve(arg string, bundling bool) ([]flagUse, error) {
+	if bundling && !strings.HasPrefix(arg, "--") && len(arg) > 2 && fs[arg] == nil {
+		return fs.unbundle(arg)
+	}
+
+	flg, value, attached := fs.lookup(arg)
+	if flg == nil {
+		return nil, fmt.Errorf("unknown flag: %s", arg)
+	}
+
+	return []flagUse{{flg, value, attached}}, nil
+}
+
+// unbundle returns the short flags bundled in an argument (e.g. `-xvf`).
+// Switches consume their implicit value and the first flag requiring a value takes the rest of
+// the bundle as its value, unless the rest is itself made of flags.
+func (fs flagset) unbundle(arg string) ([]flagUse, error) {
+	var res []flagUse
+	shorts := []rune(arg[1:])
+
+	for i, short := range shorts {
+		flg := fs[name2flag(string(short))]
+		if flg == nil {
+			return nil, fmt.Errorf("unknown flag -%c in bundle %s", short, arg)
+		}
+
+		rest := string(shorts[i+1:])
+		if flg.arity() == 0 || rest == "" {
+			res = append(res, flagUse{flg: flg})
+			continue
+		}
+
+		if fs.shorts(rest) {
+			return nil, fmt.Errorf(
+				"flag -%c requires a value and must be the last of bundle %s", short, arg)
+		}
+
+		return append(res, flagUse{flg, rest, true}), nil
+	}
+
+	return res, nil
+}
+
+// numeric returns true if a flag name starts with a digit.
+func (fs flagset) numeric() bool {
+	for flagname := range fs {
+		if name := strings.TrimLeft(flagname, "-"); name != "" && '0' <= name[0] && name[0] <= '9' {
+			return true
+		}
+	}
+
+	return false
+}
+
+// shorts returns true if every character of the given string is a short flag.
+func (fs flagset) shorts(chars string) bool {
+	for _, short := range chars {
+		if fs[name2flag(string(short))] == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// lookup returns the flag designated by an argument, along with the value attached to it.
+// The value can be attached with `--name=value` or, if the flag is not a switch, with `-nvalue`.
+func (fs flagset) lookup(arg string) (flg flag, value string, attached bool) {
+	if flg, exists := fs[arg]; exists {
+		return flg, "", false
+	}
+
+	if strings.HasPrefix(arg, "--") {
+		if flagname, value, found := strings.Cut(arg, "="); found && fs[flagname] != nil {
+			return fs[flagname], value, true
+		}
+
+		return nil, "", false
+	}
+
+	if len(arg) > 2 {
+		if flg := fs[arg[:2]]; flg != nil && flg.arity() != 0 {
+			return flg, arg[2:], true
+		}
+	}
+
+	return nil, "", false
+}
+
+// persistent returns a new flagset containing only the persistent flags.
+func (fs flagset) persistent() flagset {
+	res := flagset{}
+
+	for flagname, flg := range fs {
+		if flg.persistent() {
+			res[flagname] = flg
+		}
+	}
+
+	return res
+}
+
 ///////////////
 // Utilities //
 
@@ -175,6 +493,16 @@ func (par *Parser) errdef(err error) {
 	par.flagDefErrors = append(par.flagDefErrors, err)
 }
 
+// definitionErrors returns the definition errors of the parser and of all its subcommands.
+func (par *Parser) definitionErrors() []error {
+	res := append([]error{}, par.flagDefErrors...)
+	for _, cmd := range par.commands {
+		res = append(res, cmd.definitionErrors()...)
+	}
+
+	return res
+}
+
 func (par *Parser) registerflag(flg flag) {
 	if err := par.flags.add(name2flag(flg.names()[0]), flg); err != nil {
 		par.errdef(err)
@@ -183,6 +511,52 @@ func (par *Parser) registerflag(flg flag) {
 	par.canonical = append(par.canonical, flg)
 }
 
+// consumeValue feeds a value to its destination, positional values going to the declared
+// positional arguments.
+func (par *Parser) consumeValue(dest sink, value string) error {
+	if dest == &par.Positional {
+		return par.consumePositional(value)
+	}
+
+	return consumeArg(dest, value)
+}
+
+// consumeArg feeds a command line argument to a sink, documenting the error if any.
+func consumeArg(dest sink, arg string) error {
+	if err := dest.consume(arg); err != nil {
+		return consumeError(dest, err)
+	}
+
+	return nil
+}
+
+// consumeError wraps an error raised when consuming or validating the values of a sink.
+func consumeError(dest sink, err error) error {
+	return fmt.Errorf("when consuming %s (%s): %w", dest.names()[0], dest.kind(), err)
+}
+
+// isNegative returns true if the argument looks like a negative value, i.e. a dash followed by a
+// digit or a dot.
+func isNegative(arg string) bool {
+	return len(arg) >= 2 && arg[0] == '-' && strings.ContainsRune("0123456789.", rune(arg[1]))
+}
+
+// decodes returns true if the destination is a flag able to decode the value.
+func decodes(dest sink, value string) bool {
+	flg, ok := dest.(flag)
+	return ok && flg.decodes(value)
+}
+
+// isNegativeNumber returns true if the argument is a negative integer or decimal number.
+func isNegativeNumber(arg string) bool {
+	if !isNegative(arg) {
+		return false
+	}
+
+	_, err := strconv.ParseFloat(arg, 64)
+	return err == nil
+}
+
 func name2flag(name string) string {
 	switch len(name) {
 	case 0:
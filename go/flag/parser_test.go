@@ -57,7 +57,7 @@ func TestParser_BasicParsing(t *testing.T) {
 		{"no flags", []string{}, false},
 		{"unknown flag", []string{"-a"}, true},
 		{"empty flag name", []string{"-"}, true},
-		{"double dash", []string{"--"}, true},
+		{"double dash", []string{"--"}, false},
 		{"flag without value", []string{"--flag"}, true},
 		{"multiple flags", []string{"--flag1", "1", "--flag2", "2"}, false},
 		{"mixed flags and positional", []string{"--flag", "1", "pos1", "pos2"}, false},
@@ -291,3 +291,320 @@ func TestParser_InvalidIntArguments(t *testing.T) {
 		})
 	}
 }
+
+func TestParser_AttachedValues(t *testing.T) {
+	type dests struct {
+		num   int
+		str   string
+		ints  []int
+		hatch bool
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected dests
+		pos      []string
+	}{
+		{"long int", []string{"--num=5"}, dests{num: 5, hatch: true}, nil},
+		{"short int", []string{"-n5", "pos"}, dests{num: 5, hatch: true}, []string{"pos"}},
+		{"short string", []string{"-sfoo=bar"}, dests{str: "foo=bar", hatch: true}, nil},
+		{"empty value", []string{"--str=", "pos"}, dests{hatch: true}, []string{"pos"}},
+		{"slice", []string{"--ints=1", "-i2", "--ints", "3", "4"},
+			dests{ints: []int{1, 2, 3, 4}, hatch: true}, nil},
+		{"attached slice value stops", []string{"--ints=1", "2"},
+			dests{ints: []int{1}, hatch: true}, []string{"2"}},
+		{"bool false", []string{"--hatch=false"}, dests{}, nil},
+		{"bool true", []string{"--hatch=true"}, dests{hatch: true}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got dests
+			par := NewParser()
+			par.Int("num", &got.num, "number").Alias("n")
+			par.String("str", &got.str, "string").Alias("s")
+			par.IntSlice("ints", &got.ints, "integers").Alias("i")
+			par.Bool("hatch", &got.hatch, "hatch").Default(true).Alias("H")
+			noErr(t, par.Parse(tt.args))
+			eq(t, tt.expected, got)
+			eq(t, tt.pos, []string(par.Positional))
+		})
+	}
+
+	for _, args := range [][]string{
+		{"--num=five"}, {"--hatch=maybe"}, {"-Htrue"}, {"--nope=1"}, {"-x5"},
+	} {
+		t.Run("invalid "+args[0], func(t *testing.T) {
+			par := NewParser()
+			par.Int("num", new(int), "number")
+			par.Bool("hatch", new(bool), "hatch").Alias("H")
+			yesErr(t, par.Parse(args))
+		})
+	}
+}
+
+func TestParser_ShortBundling(t *testing.T) {
+	type dests struct {
+		extract bool
+		verbose bool
+		file    string
+	}
+
+	setup := func(opts ...ParserOpt) (*Parser, *dests) {
+		var res dests
+		par := NewParser(opts...)
+		par.Bool("extract", &res.extract, "extract").Alias("x")
+		par.Bool("verbose", &res.verbose, "verbose").Alias("v")
+		par.String("file", &res.file, "file").Alias("f")
+		return par, &res
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected dests
+	}{
+		{"switches", []string{"-xv"}, dests{true, true, ""}},
+		{"value in next argument", []string{"-xvf", "archive.tar"},
+			dests{true, true, "archive.tar"}},
+		{"value in bundle", []string{"-vfarchive.tar"}, dests{false, true, "archive.tar"}},
+		{"single value", []string{"-fx.tar"}, dests{false, false, "x.tar"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			par, got := setup(WithShortBundling())
+			noErr(t, par.Parse(tt.args))
+			eq(t, tt.expected, *got)
+		})
+	}
+
+	for _, args := range [][]string{{"-xfv", "archive.tar"}, {"-xy"}, {"-xvf"}} {
+		t.Run("invalid "+args[0], func(t *testing.T) {
+			par, _ := setup(WithShortBundling())
+			yesErr(t, par.Parse(args))
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		par, _ := setup()
+		yesErr(t, par.Parse([]string{"-xv"}))
+	})
+}
+
+func TestParser_EndOfFlags(t *testing.T) {
+	t.Run("positional", func(t *testing.T) {
+		var i int
+		par := NewParser()
+		par.Int("flag", &i, "test flag")
+		noErr(t, par.Parse([]string{"a", "--flag", "1", "--", "-weird.txt", "--flag", "2"}))
+		eq(t, 1, i)
+		eq(t, []string{"a", "-weird.txt", "--flag", "2"}, []string(par.Positional))
+		eq(t, []string(nil), par.Passthrough)
+	})
+
+	t.Run("passthrough", func(t *testing.T) {
+		par := NewParser(WithPassthrough())
+		par.Command("exec", "").Bool("quiet", new(bool), "quiet")
+		noErr(t, par.Parse([]string{"a", "--", "ls", "-l", "--", "dir"}))
+		eq(t, []string{"a"}, []string(par.Positional))
+		eq(t, []string{"ls", "-l", "--", "dir"}, par.Passthrough)
+
+		cmd := par.commands[0]
+		noErr(t, par.Parse([]string{"exec", "--quiet", "--", "ls"}))
+		eq(t, []string{"ls"}, cmd.Passthrough)
+	})
+
+	t.Run("missing value", func(t *testing.T) {
+		par := NewParser()
+		par.Int("flag", new(int), "test flag")
+		yesErr(t, par.Parse([]string{"--flag", "--", "1"}))
+	})
+
+	t.Run("help", func(t *testing.T) {
+		par := NewParser(WithHelp("wrap", "[FLAGS]"), WithPassthrough())
+		eq(t, `Usage: wrap [FLAGS] [-- ARGS...]
+
+Flags:
+  --help, -h  Print this help page
+`, par.Help())
+	})
+}
+
+func TestParser_NegativeNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []ParserOpt
+		args     []string
+		num      int
+		ratio    string
+		pos      []string
+		expectOk bool
+	}{
+		{"flag value", nil, []string{"--num", "-5"}, -5, "", nil, true},
+		{"decimal flag value", nil, []string{"--ratio", "-.5"}, 0, "-.5", nil, true},
+		{"positional disabled", nil, []string{"-5"}, 0, "", nil, false},
+		{"positional", []ParserOpt{WithNegativeNumbers()},
+			[]string{"-5", "--num", "-3", "-1.5"}, -3, "", []string{"-5", "-1.5"}, true},
+		{"not a number", nil, []string{"--num", "-5a"}, 0, "", nil, false},
+		{"relative value", nil, []string{"--ratio", "-1h"}, 0, "-1h", nil, true},
+		{"missing value", nil, []string{"--ratio", "--typo"}, 0, "", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				num   int
+				ratio string
+			)
+			par := NewParser(tt.opts...)
+			par.Int("num", &num, "number")
+			par.String("ratio", &ratio, "ratio")
+
+			err := par.Parse(tt.args)
+			if !tt.expectOk {
+				yesErr(t, err)
+				return
+			}
+
+			noErr(t, err)
+			eq(t, tt.num, num)
+			eq(t, tt.ratio, ratio)
+			eq(t, tt.pos, []string(par.Positional))
+		})
+	}
+
+	t.Run("slice values", func(t *testing.T) {
+		var nums []int
+		par := NewParser()
+		par.IntSlice("nums", &nums, "numbers")
+		noErr(t, par.Parse([]string{"--nums", "-1", "-2", "--nums", "3"}))
+		eq(t, []int{-1, -2, 3}, nums)
+	})
+
+	t.Run("digit-named flags", func(t *testing.T) {
+		var five bool
+		var num int
+		par := NewParser(WithNegativeNumbers())
+		par.Bool("five", &five, "five").Alias("5")
+		par.Int("num", &num, "number")
+		noErr(t, par.Parse([]string{"--num", "-4", "-5"}))
+		eq(t, -4, num)
+		eq(t, true, five)
+		yesErr(t, par.Parse([]string{"-3"}))
+	})
+}
+
+func TestParser_Negation(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected bool
+	}{
+		{"default", nil, true},
+		{"negated", []string{"--no-hatch"}, false},
+		{"last wins", []string{"--no-hatch", "--hatch"}, true},
+		{"alias then negated", []string{"-H", "--no-hatch"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hatch bool
+			par := NewParser()
+			par.Bool("hatch", &hatch, "hatch").Default(true).Alias("H")
+			noErr(t, par.Parse(tt.args))
+			eq(t, tt.expected, hatch)
+		})
+	}
+
+	t.Run("value", func(t *testing.T) {
+		par := NewParser()
+		par.Bool("hatch", new(bool), "hatch")
+		yesErr(t, par.Parse([]string{"--no-hatch=true"}))
+	})
+
+	t.Run("opt-out", func(t *testing.T) {
+		par := NewParser()
+		par.Bool("hatch", new(bool), "hatch").NoNegation()
+		yesErr(t, par.Parse([]string{"--no-hatch"}))
+	})
+
+	t.Run("not a switch", func(t *testing.T) {
+		par := NewParser()
+		par.Int("num", new(int), "number")
+		yesErr(t, par.Parse([]string{"--no-num"}))
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		par := NewParser()
+		par.Bool("hatch", new(bool), "hatch")
+		par.Bool("no-hatch", new(bool), "no hatch")
+		yesErr(t, par.Parse(nil))
+	})
+}
+
+func TestParser_OptionalBool(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected Optional[bool]
+	}{
+		{"absent", nil, Optional[bool]{}},
+		{"enabled", []string{"--color"}, Optional[bool]{Value: true, Set: true}},
+		{"disabled", []string{"--no-color"}, Optional[bool]{Value: false, Set: true}},
+		{"explicit", []string{"--color=false"}, Optional[bool]{Value: false, Set: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var color Optional[bool]
+			par := NewParser()
+			par.OptionalBool("color", &color, "colorize output")
+			noErr(t, par.Parse(tt.args))
+			eq(t, tt.expected, color)
+		})
+	}
+}
+
+func TestParser_RequiredFlags(t *testing.T) {
+	setup := func() *Parser {
+		par := NewParser()
+		par.Int("port", new(int), "port").Required().Default(80)
+		par.String("host", new(string), "host").Required().Env("TEST_HOST")
+		par.Bool("debug", new(bool), "debug")
+		return par
+	}
+
+	t.Run("all given", func(t *testing.T) {
+		noErr(t, setup().Parse([]string{"--port", "0", "--host", "localhost"}))
+	})
+
+	t.Run("from the environment", func(t *testing.T) {
+		t.Setenv("TEST_HOST", "localhost")
+		noErr(t, setup().Parse([]string{"--port", "0"}))
+	})
+
+	t.Run("all missing", func(t *testing.T) {
+		err := setup().Parse(nil)
+		yesErr(t, err)
+		eq(t, "missing required flag --port\nmissing required flag --host", err.Error())
+	})
+
+	t.Run("subcommand", func(t *testing.T) {
+		par := NewParser()
+		par.Command("run", "").Int("jobs", new(int), "jobs").Required()
+		noErr(t, par.Parse(nil))
+		yesErr(t, par.Parse([]string{"run"}))
+	})
+
+	t.Run("help", func(t *testing.T) {
+		eq(t, `Usage:
+
+Flags:
+  --port INT     port (required)
+  --host STRING  host (required) [env: TEST_HOST]
+  --[no-]debug   debug
+`, setup().Help())
+	})
+}
@@ -0,0 +1,269 @@
+// This file exports the reference documentation of a parser in Markdown and HTML.
+//
+// The documentation is made of a page for the parser and a page for each of its subcommands,
+// written one after the other and linked together through anchors.
+
+package flag
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/mooss/bagend/go/fun/eager/lie"
+)
+
+// WriteMarkdown writes the reference documentation of the parser and its subcommands to w, in
+// Markdown.
+func (par *Parser) WriteMarkdown(w io.Writer) error {
+	if par.program == "" {
+		return errors.New("reference requires a program name, see WithHelp")
+	}
+
+	var builder strings.Builder
+	for i, page := range par.pages() {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+
+		page.writeMarkdown(&builder)
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// WriteHTML writes the reference documentation of the parser and its subcommands to w, as an HTML
+// fragment made of one section per page.
+func (par *Parser) WriteHTML(w io.Writer) error {
+	if par.program == "" {
+		return errors.New("reference requires a program name, see WithHelp")
+	}
+
+	var builder strings.Builder
+	for _, page := range par.pages() {
+		page.writeHTML(&builder)
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+//////////
+// Page //
+//////////
+
+// refPage is the documentation of a single parser, independent of the output format.
+type refPage struct {
+	title       string
+	usage       string
+	arguments   refTable
+	flags       []refTable // Default section, named sections and global flags.
+	constraints []string
+	commands    refTable
+}
+
+// refTable is a titled table, whose cells are formatted as code except for the last column.
+type refTable struct {
+	title  string
+	header []string
+	rows   [][]string
+	links  bool // The first column holds page titles that are linked instead of formatted as code.
+}
+
+// flagHeader is the header of the flag tables.
+var flagHeader = []string{"Name", "Aliases", "Type", "Default", "Env", "Description"}
+
+// pages returns the documentation pages of the parser and its subcommands, depth first.
+func (par *Parser) pages() []refPage {
+	return lie.Map((*Parser).page, par.tree())
+}
+
+// page returns the documentation page of the parser.
+func (par *Parser) page() refPage {
+	res := refPage{
+		title:    par.program,
+		usage:    par.usageLine(),
+		commands: refTable{title: "Commands", header: []string{"Name", "Usage"}, links: true},
+	}
+
+	res.arguments = refTable{title: "Arguments", header: []string{"Name", "Description"}}
+	for _, arg := range par.arguments {
+		res.arguments.rows = append(res.arguments.rows, []string{arg.names()[0], arg.docline()})
+	}
+
+	visible := par.shown()
+	res.flags = append(res.flags, par.flagTable("Flags", inSection(visible, "")))
+	for _, section := range sections(visible) {
+		res.flags = append(res.flags, par.flagTable(section, inSection(visible, section)))
+	}
+
+	res.flags = append(res.flags, par.flagTable("Global flags", par.shownInherited()))
+
+	for _, cns := range par.constraints {
+		res.constraints = append(res.constraints, cns.describe())
+	}
+
+	for _, cmd := range par.commands {
+		res.commands.rows = append(res.commands.rows, []string{cmd.program, cmd.summary()})
+	}
+
+	return res
+}
+
+// flagTable returns the table documenting the given flags.
+func (par *Parser) flagTable(title string, flags []flag) refTable {
+	res := refTable{title: title, header: flagHeader}
+	for _, flg := range flags {
+		names := documentedNames(flg)
+		def, description := flg.defaultValue(), flg.docline()
+		if flg.isRequired() {
+			def, description = "", description+" (required)"
+		}
+
+		res.rows = append(res.rows, []string{
+			names[0],
+			strings.Join(names[1:], ", "),
+			valueType(flg),
+			def,
+			par.envVar(flg),
+			description,
+		})
+	}
+
+	return res
+}
+
+// valueType returns the type of the values of a flag, as shown in the documentation.
+func valueType(flg flag) string {
+	if placeholder := flg.placeholder(); placeholder != "" {
+		return placeholder
+	}
+
+	if flg.arity() == 0 {
+		return "switch"
+	}
+
+	return ""
+}
+
+// anchor returns the identifier of a page, e.g. `tool-remote-add`.
+func anchor(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), "-"))
+}
+
+//////////////
+// Markdown //
+//////////////
+
+func (page refPage) writeMarkdown(builder *strings.Builder) {
+	fmt.Fprintf(builder, "# %s\n\n```\n%s\n```\n", page.title, page.usage)
+
+	if len(page.arguments.rows) > 0 {
+		page.arguments.writeMarkdown(builder)
+	}
+
+	for _, table := range page.flags {
+		if len(table.rows) > 0 {
+			table.writeMarkdown(builder)
+		}
+	}
+
+	if len(page.constraints) > 0 {
+		builder.WriteString("\n## Constraints\n\n")
+		for _, cns := range page.constraints {
+			builder.WriteString("- " + cns + "\n")
+		}
+	}
+
+	if len(page.commands.rows) > 0 {
+		page.commands.writeMarkdown(builder)
+	}
+}
+
+func (table refTable) writeMarkdown(builder *strings.Builder) {
+	fmt.Fprintf(builder, "\n## %s\n\n", table.title)
+	builder.WriteString("| " + strings.Join(table.header, " | ") + " |\n")
+	builder.WriteString(strings.Repeat("| --- ", len(table.header)) + "|\n")
+
+	for _, row := range table.rows {
+		cells := lie.Map(markdownEscaper.Replace, row)
+		for i, cell := range cells[:len(cells)-1] {
+			switch {
+			case cell == "":
+			case table.links && i == 0:
+				cells[i] = "[" + cell + "](#" + anchor(row[i]) + ")"
+			default:
+				cells[i] = "`" + cell + "`"
+			}
+		}
+
+		builder.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+}
+
+// markdownEscaper escapes the characters that would break a Markdown table.
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+//////////
+// HTML //
+//////////
+
+func (page refPage) writeHTML(builder *strings.Builder) {
+	fmt.Fprintf(builder, "<section id=\"%s\">\n<h1>%s</h1>\n<pre>%s</pre>\n",
+		html.EscapeString(anchor(page.title)), html.EscapeString(page.title),
+		html.EscapeString(page.usage))
+
+	if len(page.arguments.rows) > 0 {
+		page.arguments.writeHTML(builder)
+	}
+
+	for _, table := range page.flags {
+		if len(table.rows) > 0 {
+			table.writeHTML(builder)
+		}
+	}
+
+	if len(page.constraints) > 0 {
+		builder.WriteString("<h2>Constraints</h2>\n<ul>\n")
+		for _, cns := range page.constraints {
+			builder.WriteString("<li>" + html.EscapeString(cns) + "</li>\n")
+		}
+		builder.WriteString("</ul>\n")
+	}
+
+	if len(page.commands.rows) > 0 {
+		page.commands.writeHTML(builder)
+	}
+
+	builder.WriteString("</section>\n")
+}
+
+func (table refTable) writeHTML(builder *strings.Builder) {
+	fmt.Fprintf(builder, "<h2>%s</h2>\n<table>\n<thead>\n<tr>", html.EscapeString(table.title))
+	for _, cell := range table.header {
+		builder.WriteString("<th>" + html.EscapeString(cell) + "</th>")
+	}
+	builder.WriteString("</tr>\n</thead>\n<tbody>\n")
+
+	for _, row := range table.rows {
+		builder.WriteString("<tr>")
+		for i, cell := range row {
+			escaped := html.EscapeString(cell)
+			switch {
+			case cell == "" || i == len(row)-1:
+			case table.links && i == 0:
+				escaped = `<a href="#` + html.EscapeString(anchor(cell)) + `">` + escaped + "</a>"
+			default:
+				escaped = "<code>" + escaped + "</code>"
+			}
+
+			builder.WriteString("<td>" + escaped + "</td>")
+		}
+		builder.WriteString("</tr>\n")
+	}
+
+	builder.WriteString("</tbody>\n</table>\n")
+}
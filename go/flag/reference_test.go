@@ -0,0 +1,107 @@
+package flag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParser_Reference(t *testing.T) {
+	setup := func() *Parser {
+		par := NewParser(WithHelp("tool", "[FLAGS]"))
+		Arg[String](par, "SRC", new(string), "source file")
+		par.Choice("format", new(string), []string{"json", "yaml"}, "output format").
+			Alias("f").Default("json").Env("FORMAT")
+		par.Bool("color", new(bool), "colored | output")
+		par.Int("retries", new(int), "number of retries").Section("Network")
+		par.Int("secret", new(int), "hidden flag").Hidden()
+		remote := par.Command("remote", "[FLAGS] NAME")
+		remote.String("host", new(string), "remote <host>").Required()
+		return par
+	}
+
+	t.Run("markdown", func(t *testing.T) {
+		var out strings.Builder
+		noErr(t, setup().WriteMarkdown(&out))
+		eq(t, "# tool\n\n```\nUsage: tool [FLAGS] SRC\n```\n"+`
+## Arguments
+
+| Name | Description |
+| --- | --- |
+| `+"`SRC`"+` | source file |
+
+## Flags
+
+| Name | Aliases | Type | Default | Env | Description |
+| --- | --- | --- | --- | --- | --- |
+| `+"`--help` | `-h` | `switch`"+` |  |  | Print this help page |
+| `+"`--format` | `-f` | `{json,yaml}` | `json` | `FORMAT`"+` | output format |
+| `+"`--[no-]color`"+` |  | `+"`switch`"+` |  |  | colored \| output |
+
+## Network
+
+| Name | Aliases | Type | Default | Env | Description |
+| --- | --- | --- | --- | --- | --- |
+| `+"`--retries`"+` |  | `+"`INT`"+` |  |  | number of retries |
+
+## Commands
+
+| Name | Usage |
+| --- | --- |
+| [tool remote](#tool-remote) | [FLAGS] NAME |
+
+# tool remote
+
+`+"```\nUsage: tool remote [FLAGS] NAME\n```"+`
+
+## Flags
+
+| Name | Aliases | Type | Default | Env | Description |
+| --- | --- | --- | --- | --- | --- |
+| `+"`--host`"+` |  | `+"`STRING`"+` |  |  | remote <host> (required) |
+
+## Global flags
+
+| Name | Aliases | Type | Default | Env | Description |
+| --- | --- | --- | --- | --- | --- |
+| `+"`--help` | `-h` | `switch`"+` |  |  | Print this help page |
+`, out.String())
+	})
+
+	t.Run("html", func(t *testing.T) {
+		var out strings.Builder
+		noErr(t, setup().WriteHTML(&out))
+		html := out.String()
+		for _, expected := range []string{
+			"<section id=\"tool\">\n<h1>tool</h1>\n<pre>Usage: tool [FLAGS] SRC</pre>\n",
+			"<tr><th>Name</th><th>Aliases</th><th>Type</th><th>Default</th><th>Env</th>" +
+				"<th>Description</th></tr>",
+			"<tr><td><code>--format</code></td><td><code>-f</code></td>" +
+				"<td><code>{json,yaml}</code></td><td><code>json</code></td>" +
+				"<td><code>FORMAT</code></td><td>output format</td></tr>",
+			"<h2>Network</h2>",
+			`<tr><td><a href="#tool-remote">tool remote</a></td><td>[FLAGS] NAME</td></tr>`,
+			"<section id=\"tool-remote\">",
+			"<td>remote &lt;host&gt; (required)</td>",
+		} {
+			if !strings.Contains(html, expected) {
+				t.Errorf("expected %q in:\n%s", expected, html)
+			}
+		}
+
+		if strings.Contains(html, "secret") {
+			t.Errorf("hidden flag in:\n%s", html)
+		}
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		var first, second strings.Builder
+		noErr(t, setup().WriteMarkdown(&first))
+		noErr(t, setup().WriteMarkdown(&second))
+		eq(t, first.String(), second.String())
+	})
+
+	t.Run("missing program name", func(t *testing.T) {
+		yesErr(t, NewParser().WriteMarkdown(&strings.Builder{}))
+		yesErr(t, NewParser().WriteHTML(&strings.Builder{}))
+	})
+}
@@ -2,6 +2,13 @@
 
 package flag
 
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"time"
+)
+
 ////////////////////////////
 // Generic implementation //
 
@@ -9,13 +16,23 @@ package flag
 // Registering different flags to the same destination is undefined behavior.
 func Register[D Decoder[T], T any](
 	par *Parser, name string, dest *T, docline string,
+) FluentFlag[T] {
+	var decoder D
+	return RegisterWith(par, decoder, name, dest, docline)
+}
+
+// RegisterWith registers a singleton flag to a parser, using a configured decoder.
+// Registering different flags to the same destination is undefined behavior.
+func RegisterWith[D Decoder[T], T any](
+	par *Parser, decoder D, name string, dest *T, docline string,
 ) FluentFlag[T] {
 	flg := singletonflag[T, D]{
-		flagBase[T]{
+		flagBase: flagBase[T]{
 			dest:       dest,
 			docLine:    docline,
 			namesStore: []string{name},
 		},
+		decoder: decoder,
 	}
 
 	par.registerflag(&flg)
@@ -27,13 +44,57 @@ func Register[D Decoder[T], T any](
 // Registering different flags to the same destination is undefined behavior.
 func RegisterSlice[Dec Decoder[T], T any](
 	par *Parser, name string, dest *[]T, docline string,
+) FluentFlag[[]T] {
+	var decoder Dec
+	return RegisterSliceWith(par, decoder, name, dest, docline)
+}
+
+// RegisterSliceWith registers a slice flag to a parser, using a configured decoder.
+// Registering different flags to the same destination is undefined behavior.
+func RegisterSliceWith[Dec Decoder[T], T any](
+	par *Parser, decoder Dec, name string, dest *[]T, docline string,
 ) FluentFlag[[]T] {
 	flg := sliceFlag[T, Dec]{
-		flagBase[[]T]{
+		flagBase: flagBase[[]T]{
 			dest:       dest,
 			docLine:    docline,
 			namesStore: []string{name},
 		},
+		decoder: decoder,
+	}
+
+	par.registerflag(&flg)
+
+	return &flg
+}
+
+// RegisterMap registers a map flag to a parser, consuming `key=value` pairs.
+// Registering different flags to the same destination is undefined behavior.
+func RegisterMap[KD Decoder[K], VD Decoder[V], K comparable, V any](
+	par *Parser, name string, dest *map[K]V, docline string, options MapOptions,
+) FluentFlag[map[K]V] {
+	var (
+		keyDecoder   KD
+		valueDecoder VD
+	)
+	return RegisterMapWith(par, keyDecoder, valueDecoder, name, dest, docline, options)
+}
+
+// RegisterMapWith registers a map flag to a parser, using configured decoders.
+// Registering different flags to the same destination is undefined behavior.
+func RegisterMapWith[KD Decoder[K], VD Decoder[V], K comparable, V any](
+	par *Parser, keyDecoder KD, valueDecoder VD,
+	name string, dest *map[K]V, docline string, options MapOptions,
+) FluentFlag[map[K]V] {
+	flg := mapFlag[K, V, KD, VD]{
+		flagBase: flagBase[map[K]V]{
+			dest:       dest,
+			docLine:    docline,
+			namesStore: []string{name},
+		},
+		keyDecoder:   keyDecoder,
+		valueDecoder: valueDecoder,
+		options:      options,
 	}
 
 	par.registerflag(&flg)
@@ -48,18 +109,240 @@ func (par *Parser) Int(name string, dest *int, docline string) FluentFlag[int] {
 	return Register[Int](par, name, dest, docline)
 }
 
+func (par *Parser) Int8(name string, dest *int8, docline string) FluentFlag[int8] {
+	return Register[Int8](par, name, dest, docline)
+}
+
+func (par *Parser) Int16(name string, dest *int16, docline string) FluentFlag[int16] {
+	return Register[Int16](par, name, dest, docline)
+}
+
+func (par *Parser) Int32(name string, dest *int32, docline string) FluentFlag[int32] {
+	return Register[Int32](par, name, dest, docline)
+}
+
+func (par *Parser) Int64(name string, dest *int64, docline string) FluentFlag[int64] {
+	return Register[Int64](par, name, dest, docline)
+}
+
+func (par *Parser) Uint(name string, dest *uint, docline string) FluentFlag[uint] {
+	return Register[Uint](par, name, dest, docline)
+}
+
+func (par *Parser) Uint8(name string, dest *uint8, docline string) FluentFlag[uint8] {
+	return Register[Uint8](par, name, dest, docline)
+}
+
+func (par *Parser) Uint16(name string, dest *uint16, docline string) FluentFlag[uint16] {
+	return Register[Uint16](par, name, dest, docline)
+}
+
+func (par *Parser) Uint32(name string, dest *uint32, docline string) FluentFlag[uint32] {
+	return Register[Uint32](par, name, dest, docline)
+}
+
+func (par *Parser) Uint64(name string, dest *uint64, docline string) FluentFlag[uint64] {
+	return Register[Uint64](par, name, dest, docline)
+}
+
+func (par *Parser) Float32(name string, dest *float32, docline string) FluentFlag[float32] {
+	return Register[Float32](par, name, dest, docline)
+}
+
+func (par *Parser) Float64(name string, dest *float64, docline string) FluentFlag[float64] {
+	return Register[Float64](par, name, dest, docline)
+}
+
 func (par *Parser) String(name string, dest *string, docline string) FluentFlag[string] {
 	return Register[String](par, name, dest, docline)
 }
 
+func (par *Parser) Path(name string, dest *string, docline string) FluentFlag[string] {
+	return Register[Path](par, name, dest, docline)
+}
+
 func (par *Parser) Bool(name string, dest *bool, docline string) FluentFlag[bool] {
 	return Register[Bool](par, name, dest, docline)
 }
 
+func (par *Parser) Duration(
+	name string, dest *time.Duration, docline string,
+) FluentFlag[time.Duration] {
+	return Register[Duration](par, name, dest, docline)
+}
+
+func (par *Parser) Time(name string, dest *time.Time, docline string) FluentFlag[time.Time] {
+	return Register[Time](par, name, dest, docline)
+}
+
+func (par *Parser) ByteSize(name string, dest *int64, docline string) FluentFlag[int64] {
+	return Register[ByteSize[int64]](par, name, dest, docline)
+}
+
+func (par *Parser) Quantity(name string, dest *float64, docline string) FluentFlag[float64] {
+	return Register[Quantity](par, name, dest, docline)
+}
+
+func (par *Parser) URL(name string, dest **url.URL, docline string) FluentFlag[*url.URL] {
+	return Register[URL](par, name, dest, docline)
+}
+
+func (par *Parser) AddrPort(
+	name string, dest *netip.AddrPort, docline string,
+) FluentFlag[netip.AddrPort] {
+	return Register[AddrPort](par, name, dest, docline)
+}
+
+func (par *Parser) HostPort(name string, dest *string, docline string) FluentFlag[string] {
+	return Register[HostPort](par, name, dest, docline)
+}
+
+func (par *Parser) Addr(name string, dest *netip.Addr, docline string) FluentFlag[netip.Addr] {
+	return Register[Addr](par, name, dest, docline)
+}
+
+func (par *Parser) Prefix(
+	name string, dest *netip.Prefix, docline string,
+) FluentFlag[netip.Prefix] {
+	return Register[Prefix](par, name, dest, docline)
+}
+
+func (par *Parser) MAC(
+	name string, dest *net.HardwareAddr, docline string,
+) FluentFlag[net.HardwareAddr] {
+	return Register[MAC](par, name, dest, docline)
+}
+
+func (par *Parser) OptionalBool(
+	name string, dest *Optional[bool], docline string,
+) FluentFlag[Optional[bool]] {
+	return Register[OptionalBool](par, name, dest, docline)
+}
+
+// Counter registers a flag incremented each time it is given, up to limit unless it is 0.
+// An explicit value can also be given, e.g. `--verbose=3`.
+func (par *Parser) Counter(name string, dest *int, limit int, docline string) FluentFlag[int] {
+	flg := counterFlag{
+		flagBase: flagBase[int]{
+			dest:       dest,
+			docLine:    docline,
+			namesStore: []string{name},
+		},
+		limit: limit,
+	}
+
+	par.registerflag(&flg)
+
+	return &flg
+}
+
+// Choice registers a string flag accepting only the given values.
+func (par *Parser) Choice(
+	name string, dest *string, values []string, docline string,
+) FluentFlag[string] {
+	return RegisterWith(par, Choice[string]{Values: values}, name, dest, docline)
+}
+
+////////////////////////////
+// Specific types: slices //
+
 func (par *Parser) IntSlice(name string, dest *[]int, docline string) FluentFlag[[]int] {
 	return RegisterSlice[Int](par, name, dest, docline)
 }
 
+func (par *Parser) Int8Slice(name string, dest *[]int8, docline string) FluentFlag[[]int8] {
+	return RegisterSlice[Int8](par, name, dest, docline)
+}
+
+func (par *Parser) Int16Slice(name string, dest *[]int16, docline string) FluentFlag[[]int16] {
+	return RegisterSlice[Int16](par, name, dest, docline)
+}
+
+func (par *Parser) Int32Slice(name string, dest *[]int32, docline string) FluentFlag[[]int32] {
+	return RegisterSlice[Int32](par, name, dest, docline)
+}
+
+func (par *Parser) Int64Slice(name string, dest *[]int64, docline string) FluentFlag[[]int64] {
+	return RegisterSlice[Int64](par, name, dest, docline)
+}
+
+func (par *Parser) UintSlice(name string, dest *[]uint, docline string) FluentFlag[[]uint] {
+	return RegisterSlice[Uint](par, name, dest, docline)
+}
+
+func (par *Parser) Uint8Slice(name string, dest *[]uint8, docline string) FluentFlag[[]uint8] {
+	return RegisterSlice[Uint8](par, name, dest, docline)
+}
+
+func (par *Parser) Uint16Slice(name string, dest *[]uint16, docline string) FluentFlag[[]uint16] {
+	return RegisterSlice[Uint16](par, name, dest, docline)
+}
+
+func (par *Parser) Uint32Slice(name string, dest *[]uint32, docline string) FluentFlag[[]uint32] {
+	return RegisterSlice[Uint32](par, name, dest, docline)
+}
+
+func (par *Parser) Uint64Slice(name string, dest *[]uint64, docline string) FluentFlag[[]uint64] {
+	return RegisterSlice[Uint64](par, name, dest, docline)
+}
+
+func (par *Parser) Float32Slice(
+	name string, dest *[]float32, docline string,
+) FluentFlag[[]float32] {
+	return RegisterSlice[Float32](par, name, dest, docline)
+}
+
+func (par *Parser) Float64Slice(
+	name string, dest *[]float64, docline string,
+) FluentFlag[[]float64] {
+	return RegisterSlice[Float64](par, name, dest, docline)
+}
+
+func (par *Parser) URLSlice(name string, dest *[]*url.URL, docline string) FluentFlag[[]*url.URL] {
+	return RegisterSlice[URL](par, name, dest, docline)
+}
+
+func (par *Parser) AddrPortSlice(
+	name string, dest *[]netip.AddrPort, docline string,
+) FluentFlag[[]netip.AddrPort] {
+	return RegisterSlice[AddrPort](par, name, dest, docline)
+}
+
+func (par *Parser) HostPortSlice(name string, dest *[]string, docline string) FluentFlag[[]string] {
+	return RegisterSlice[HostPort](par, name, dest, docline)
+}
+
+func (par *Parser) AddrSlice(
+	name string, dest *[]netip.Addr, docline string,
+) FluentFlag[[]netip.Addr] {
+	return RegisterSlice[Addr](par, name, dest, docline)
+}
+
+func (par *Parser) PrefixSlice(
+	name string, dest *[]netip.Prefix, docline string,
+) FluentFlag[[]netip.Prefix] {
+	return RegisterSlice[Prefix](par, name, dest, docline)
+}
+
+func (par *Parser) MACSlice(
+	name string, dest *[]net.HardwareAddr, docline string,
+) FluentFlag[[]net.HardwareAddr] {
+	return RegisterSlice[MAC](par, name, dest, docline)
+}
+
 func (par *Parser) StringSlice(name string, dest *[]string, docline string) FluentFlag[[]string] {
 	return RegisterSlice[String](par, name, dest, docline)
 }
+
+func (par *Parser) PathSlice(name string, dest *[]string, docline string) FluentFlag[[]string] {
+	return RegisterSlice[Path](par, name, dest, docline)
+}
+
+//////////////////////////
+// Specific types: maps //
+
+func (par *Parser) StringMap(
+	name string, dest *map[string]string, docline string,
+) FluentFlag[map[string]string] {
+	return RegisterMap[String, String](par, name, dest, docline, MapOptions{})
+}
@@ -1,28 +1,89 @@
 package flag
 
-import "fmt"
+import (
+	"fmt"
+	"reflect"
+)
 
 // singletonFlag represents a flag that can consume exactly one value.
 // It implements both the flag and FluentFlag interfaces.
 type singletonflag[T any, D Decoder[T]] struct {
 	// flagBase implements the FluentFlag interface and part of the flag interface.
 	flagBase[T]
+	decoder D
 }
 
 ///////////////////////////////////////////
 // Rest of flag interface implementation //
 
-func (*singletonflag[T, D]) arity() int {
+func (ffs *singletonflag[T, D]) arity() int {
+	if is[Switch](ffs.decoder) {
+		return 0
+	}
+
 	return 1
 }
 
+// given considers that switches set to their zero value, e.g. `--no-tls`, are not given.
+func (ffs *singletonflag[T, D]) given() bool {
+	if !ffs.alreadySet || ffs.arity() != 0 {
+		return ffs.alreadySet
+	}
+
+	return !reflect.ValueOf(ffs.dest).Elem().IsZero()
+}
+
+func (ffs *singletonflag[T, D]) implicit() string {
+	if sw, ok := any(ffs.decoder).(Switch); ok {
+		return sw.Implicit()
+	}
+
+	return ""
+}
+
+func (ffs *singletonflag[T, D]) negation() string {
+	if neg, ok := any(ffs.decoder).(Negatable); ok && !ffs.noNegation {
+		return neg.Negated()
+	}
+
+	return ""
+}
+
+func (ffs *singletonflag[T, D]) placeholder() string {
+	return placeholder(ffs.decoder)
+}
+
+func (ffs *singletonflag[T, D]) choices() []string {
+	return choices(ffs.decoder)
+}
+
+func (ffs *singletonflag[T, D]) decodes(value string) bool {
+	_, err := ffs.decoder.Decode(value)
+	return err == nil
+}
+
+func (ffs *singletonflag[T, D]) files() fileCompletion {
+	return files(ffs.decoder)
+}
+
+func (ffs *singletonflag[T, D]) defaultValue() string {
+	if !ffs.hasDefault {
+		return ""
+	}
+
+	return formatValue(ffs.decoder, ffs.def)
+}
+
 func (ffs *singletonflag[T, D]) consume(value string) error {
-	var decoder D
-	decoded, err := decoder.Decode(value)
+	decoded, err := ffs.decoder.Decode(value)
 	if err != nil {
 		return err
 	}
 
+	if err := ffs.validate(decoded); err != nil {
+		return err
+	}
+
 	// Since dest is also a *T, the value it just decoded can simply be assigned to it.
 	*ffs.dest = decoded
 	ffs.alreadySet = true
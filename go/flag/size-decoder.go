@@ -0,0 +1,176 @@
+// This file implements the decoders of human-readable sizes and quantities, e.g. `2GiB` or `1.5k`.
+
+package flag
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+////////////////
+// Byte sizes //
+
+// byteUnits associates the byte size suffixes with their multiplier, SI suffixes being powers of
+// 1000 and IEC suffixes powers of 1024.
+var byteUnits = map[string]uint64{
+	"": 1, "b": 1,
+	"k": 1e3, "kb": 1e3, "kib": 1 << 10,
+	"m": 1e6, "mb": 1e6, "mib": 1 << 20,
+	"g": 1e9, "gb": 1e9, "gib": 1 << 30,
+	"t": 1e12, "tb": 1e12, "tib": 1 << 40,
+	"p": 1e15, "pb": 1e15, "pib": 1 << 50,
+	"e": 1e18, "eb": 1e18, "eib": 1 << 60,
+}
+
+// byteSuffixes are the canonical spellings of the byte units.
+var byteSuffixes = []string{
+	"kB", "KiB", "MB", "MiB", "GB", "GiB", "TB", "TiB", "PB", "PiB", "EB", "EiB",
+}
+
+// ByteSize implements Decoder[T], Placeholder and Formatter[T] for a number of bytes.
+// The number can be decimal and is followed by an optional case-insensitive unit, either SI (kB,
+// MB, ...) or IEC (KiB, MiB, ...).
+type ByteSize[T ~int64 | ~uint64] struct{}
+
+func (ByteSize[T]) Decode(source string) (T, error) {
+	number, unit := splitUnit(source)
+	multiplier, exists := byteUnits[strings.ToLower(unit)]
+	if !exists {
+		return 0, fmt.Errorf("unknown size unit %q", unit)
+	}
+
+	// big.Rat also accepts fractions, exponents, base prefixes and underscores.
+	value, ok := new(big.Rat).SetString(number)
+	if !ok || !isDecimal(number) {
+		return 0, &strconv.NumError{Func: "ByteSize", Num: source, Err: strconv.ErrSyntax}
+	}
+
+	value.Mul(value, new(big.Rat).SetUint64(multiplier))
+	if !value.IsInt() {
+		return 0, fmt.Errorf("%s is not a whole number of bytes", source)
+	}
+
+	var zero T
+	minimum, maximum := big.NewInt(math.MinInt64), big.NewInt(math.MaxInt64)
+	if ^zero > 0 { // Unsigned.
+		minimum, maximum = big.NewInt(0), new(big.Int).SetUint64(math.MaxUint64)
+	}
+
+	if value.Num().Cmp(minimum) < 0 || value.Num().Cmp(maximum) > 0 {
+		return 0, &OverflowError{Value: source, Type: fmt.Sprintf("%T", zero)}
+	}
+
+	if ^zero > 0 {
+		return T(value.Num().Uint64()), nil
+	}
+
+	return T(value.Num().Int64()), nil
+}
+
+func (ByteSize[T]) Placeholder() string { return "SIZE" }
+
+func (ByteSize[T]) Format(value T) string {
+	return FormatBytes(value)
+}
+
+// FormatBytes returns the shortest exact representation of a number of bytes.
+func FormatBytes[T ~int64 | ~uint64](value T) string {
+	res := fmt.Sprintf("%dB", value)
+
+	magnitude := uint64(value)
+	if value < 0 {
+		magnitude = uint64(-value)
+	}
+
+	for _, unit := range byteSuffixes {
+		multiplier := byteUnits[strings.ToLower(unit)]
+		if magnitude == 0 || magnitude%multiplier != 0 {
+			continue
+		}
+
+		candidate := fmt.Sprintf("%d%s", value/T(multiplier), unit)
+		if len(candidate) <= len(res) {
+			res = candidate
+		}
+	}
+
+	return res
+}
+
+////////////////
+// Quantities //
+
+// siPrefixes associates the SI prefixes with their multiplier.
+var siPrefixes = []struct {
+	prefix     string
+	multiplier float64
+}{
+	{"E", 1e18}, {"P", 1e15}, {"T", 1e12}, {"G", 1e9}, {"M", 1e6}, {"k", 1e3},
+	{"", 1}, {"m", 1e-3}, {"u", 1e-6}, {"n", 1e-9},
+}
+
+// Quantity implements Decoder[float64], Placeholder and Formatter[float64] for numbers followed by
+// an optional SI prefix, e.g. `10k` or `2.5M`.
+// `K` is accepted as an alias of `k` and `µ` as an alias of `u`.
+type Quantity struct{}
+
+func (Quantity) Decode(source string) (float64, error) {
+	number, prefix := splitUnit(source)
+	prefix = strings.NewReplacer("K", "k", "µ", "u").Replace(prefix)
+
+	for _, si := range siPrefixes {
+		if si.prefix != prefix {
+			continue
+		}
+
+		value, err := decodeFloat[float64](number, 64, false)
+		if err != nil {
+			return 0, err
+		}
+
+		return value * si.multiplier, nil
+	}
+
+	return 0, fmt.Errorf("unknown SI prefix %q", prefix)
+}
+
+func (Quantity) Placeholder() string { return "QUANTITY" }
+
+func (Quantity) Format(value float64) string {
+	return FormatQuantity(value)
+}
+
+// FormatQuantity returns a representation of a number using the largest fitting SI prefix.
+func FormatQuantity(value float64) string {
+	for _, si := range siPrefixes {
+		if math.Abs(value) >= si.multiplier {
+			return strconv.FormatFloat(value/si.multiplier, 'f', -1, 64) + si.prefix
+		}
+	}
+
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+///////////////
+// Utilities //
+
+// isDecimal returns true if the number is made of decimal digits with an optional sign and an
+// optional decimal point.
+func isDecimal(number string) bool {
+	digits := strings.TrimLeft(number, "+-")
+	if len(number)-len(digits) > 1 || strings.Count(digits, ".") > 1 {
+		return false
+	}
+
+	digits = strings.Replace(digits, ".", "", 1)
+	return digits != "" && strings.Trim(digits, "0123456789") == ""
+}
+
+// splitUnit splits a source into its numeric prefix and the unit following it.
+func splitUnit(source string) (number, unit string) {
+	end := strings.LastIndexAny(source, "0123456789.") + 1
+	return source[:end], source[end:]
+}
@@ -0,0 +1,90 @@
+package flag
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected int64
+	}{
+		{"123", 123},
+		{"123B", 123},
+		{"123KB", 123000},
+		{"2kb", 2000},
+		{"2GiB", 2 << 30},
+		{"1.5k", 1500},
+		{"0.5KiB", 512},
+		{"-1MiB", -1 << 20},
+		{"010", 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			decodeExpect(t, ByteSize[int64]{}, tt.source, tt.expected)
+		})
+	}
+
+	decodeExpect(t, ByteSize[uint64]{}, "15EiB", uint64(15)<<60)
+
+	invalid := []string{
+		"", "KB", "1.2.3KB", "12XB", "1.5B", "1/2KB", "1e3", "0x10", "0b1010", "1_000", "--1", ".",
+	}
+	for _, source := range invalid {
+		t.Run("invalid "+source, func(t *testing.T) {
+			yesErr(t, second(ByteSize[int64]{}.Decode(source)))
+		})
+	}
+
+	for _, err := range []error{
+		second(ByteSize[int64]{}.Decode("8EiB")),
+		second(ByteSize[uint64]{}.Decode("16EiB")),
+		second(ByteSize[uint64]{}.Decode("-1")),
+	} {
+		var overflow *OverflowError
+		if !errors.As(err, &overflow) {
+			t.Errorf("expected an overflow error, got %v", err)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	eq(t, "0B", FormatBytes(int64(0)))
+	eq(t, "1500B", FormatBytes(int64(1500)))
+	eq(t, "1kB", FormatBytes(int64(1000)))
+	eq(t, "1KiB", FormatBytes(int64(1024)))
+	eq(t, "-3MiB", FormatBytes(int64(-3<<20)))
+	eq(t, "2GB", FormatBytes(uint64(2e9)))
+	eq(t, "1024kB", FormatBytes(uint64(1024000)))
+}
+
+func TestQuantity(t *testing.T) {
+	decodeExpect(t, Quantity{}, "10k", 10000.)
+	decodeExpect(t, Quantity{}, "10K", 10000.)
+	decodeExpect(t, Quantity{}, "2.5M", 2.5e6)
+	decodeExpect(t, Quantity{}, "-3", -3.)
+	decodeExpect(t, Quantity{}, "250m", .25)
+	yesErr(t, second(Quantity{}.Decode("10x")))
+	yesErr(t, second(Quantity{}.Decode("M")))
+
+	eq(t, "2.5M", FormatQuantity(2.5e6))
+	eq(t, "999", FormatQuantity(999))
+	eq(t, "250m", FormatQuantity(.25))
+	eq(t, "0", FormatQuantity(0))
+}
+
+func TestParser_SizeHelp(t *testing.T) {
+	par := NewParser()
+	par.ByteSize("cache", new(int64), "cache size").Default(64 << 20)
+	par.Quantity("rate", new(float64), "request rate").Default(1500)
+	par.Int("jobs", new(int), "jobs").Default(4)
+	eq(t, `Usage:
+
+Flags:
+  --cache SIZE     cache size (default: 64MiB)
+  --rate QUANTITY  request rate (default: 1.5k)
+  --jobs INT       jobs (default: 4)
+`, par.Help())
+}
@@ -2,37 +2,88 @@
 
 package flag
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mooss/bagend/go/fun/eager/lie"
+)
 
 // sliceFlag represents a flag that can consume multiple values.
 // It implements both the flag and FluentFlag interfaces.
 type sliceFlag[T any, D Decoder[T]] struct {
 	// flagBase implements the FluentFlag interface and part of the flag interface.
 	flagBase[[]T]
+	decoder D
 }
 
 ///////////////////////////////////////////
 // Rest of flag interface implementation //
 
 func (sf *sliceFlag[T, D]) consume(value string) error {
-	var decoder D
 	// Decode one value.
-	decoded, err := decoder.Decode(value)
+	decoded, err := sf.decoder.Decode(value)
 	if err != nil {
 		return err
 	}
 
-	// Add the decoded value to the storage.
+	// Add the decoded value to the storage, the complete slice is validated by check.
 	*sf.dest = append(*sf.dest, decoded)
 	sf.alreadySet = true
 
 	return nil
 }
 
+func (sf *sliceFlag[T, D]) check() error {
+	if !sf.alreadySet {
+		return nil
+	}
+
+	return sf.validate(*sf.dest)
+}
+
 func (*sliceFlag[T, D]) arity() int {
 	return -1 // A slice can always consume more elements.
 }
 
+func (*sliceFlag[T, D]) implicit() string {
+	return ""
+}
+
+func (*sliceFlag[T, D]) negation() string {
+	return ""
+}
+
+func (sf *sliceFlag[T, D]) placeholder() string {
+	if res := placeholder(sf.decoder); res != "" {
+		return res + "..."
+	}
+
+	return ""
+}
+
+func (sf *sliceFlag[T, D]) choices() []string {
+	return choices(sf.decoder)
+}
+
+func (sf *sliceFlag[T, D]) decodes(value string) bool {
+	_, err := sf.decoder.Decode(value)
+	return err == nil
+}
+
+func (sf *sliceFlag[T, D]) files() fileCompletion {
+	return files(sf.decoder)
+}
+
+func (sf *sliceFlag[T, D]) defaultValue() string {
+	if !sf.hasDefault {
+		return ""
+	}
+
+	formatted := lie.Map(func(value T) string { return formatValue(sf.decoder, value) }, sf.def)
+	return strings.Join(formatted, ",")
+}
+
 func (*sliceFlag[T, D]) kind() string {
 	var zero T
 	return fmt.Sprintf("slice of %T", zero)
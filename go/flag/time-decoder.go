@@ -0,0 +1,260 @@
+// This file implements the time-related decoders.
+
+package flag
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mooss/bagend/go/fun/eager/lie"
+)
+
+//////////////
+// Duration //
+
+// Duration implements Decoder[time.Duration], Placeholder and Formatter.
+// On top of the units accepted by time.ParseDuration, it accepts days (d) and weeks (w).
+type Duration struct{}
+
+func (Duration) Decode(source string) (time.Duration, error) {
+	return parseDuration(source)
+}
+
+func (Duration) Placeholder() string { return "DURATION" }
+
+// Format formats a duration without its trailing zero units, e.g. `1h` instead of `1h0m0s`.
+func (Duration) Format(value time.Duration) string {
+	res := value.String()
+	if strings.HasSuffix(res, "m0s") {
+		res = strings.TrimSuffix(res, "0s")
+	}
+
+	if strings.HasSuffix(res, "h0m") {
+		res = strings.TrimSuffix(res, "0m")
+	}
+
+	return res
+}
+
+//////////
+// Time //
+
+// Time implements Decoder[time.Time], Placeholder and Formatter.
+type Time struct {
+	// Layouts are the formats tried in order, defaulting to RFC3339 and date only.
+	Layouts []string
+
+	// Location is used for layouts without time zone, defaulting to UTC.
+	Location *time.Location
+}
+
+func (dec Time) Decode(source string) (time.Time, error) {
+	location := dec.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	for _, layout := range dec.layouts() {
+		if res, err := time.ParseInLocation(layout, source, location); err == nil {
+			return res, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%q does not match %s", source, dec.Placeholder())
+}
+
+func (dec Time) Placeholder() string {
+	return strings.Join(lie.Map(layoutName, dec.layouts()), "|")
+}
+
+// Format formats a time with the first layout.
+func (dec Time) Format(value time.Time) string {
+	return value.Format(dec.layouts()[0])
+}
+
+func (dec Time) layouts() []string {
+	if len(dec.Layouts) == 0 {
+		return []string{time.RFC3339, time.DateOnly}
+	}
+
+	return dec.Layouts
+}
+
+// layoutName returns a readable version of the most common layouts.
+func layoutName(layout string) string {
+	switch layout {
+	case time.RFC3339:
+		return "RFC3339"
+	case time.DateOnly:
+		return "YYYY-MM-DD"
+	case time.DateTime:
+		return "YYYY-MM-DD_HH:MM:SS"
+	case time.TimeOnly:
+		return "HH:MM:SS"
+	default:
+		return layout
+	}
+}
+
+///////////////
+// Unix time //
+
+// UnixTime implements Decoder[time.Time], Placeholder and Formatter for Unix epoch timestamps.
+type UnixTime struct {
+	// Millis interprets timestamps as milliseconds instead of seconds.
+	Millis bool
+}
+
+func (dec UnixTime) Decode(source string) (time.Time, error) {
+	epoch, err := strconv.ParseInt(source, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if dec.Millis {
+		return time.UnixMilli(epoch).UTC(), nil
+	}
+
+	return time.Unix(epoch, 0).UTC(), nil
+}
+
+func (dec UnixTime) Placeholder() string {
+	if dec.Millis {
+		return "EPOCH_MS"
+	}
+
+	return "EPOCH"
+}
+
+func (dec UnixTime) Format(value time.Time) string {
+	if dec.Millis {
+		return strconv.FormatInt(value.UnixMilli(), 10)
+	}
+
+	return strconv.FormatInt(value.Unix(), 10)
+}
+
+///////////////////
+// Relative time //
+
+// RelativeTime implements Decoder[time.Time], Placeholder and Formatter.
+// It accepts `now`, a signed duration relative to now (e.g. `-3d`, `now-2h`) or an absolute time.
+type RelativeTime struct {
+	// Now returns the reference time, defaulting to time.Now.
+	Now func() time.Time
+
+	// Absolute decodes the times that are not relative.
+	Absolute Time
+}
+
+func (dec RelativeTime) Decode(source string) (time.Time, error) {
+	now := time.Now
+	if dec.Now != nil {
+		now = dec.Now
+	}
+
+	offset, relative := strings.CutPrefix(source, "now")
+	if !relative && !strings.HasPrefix(source, "-") && !strings.HasPrefix(source, "+") {
+		return dec.Absolute.Decode(source)
+	}
+
+	if offset == "" {
+		return now(), nil
+	}
+
+	if offset[0] != '-' && offset[0] != '+' {
+		return time.Time{}, fmt.Errorf(
+			"invalid relative time %q, expected a sign after now", source)
+	}
+
+	duration, err := parseDuration(offset)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return now().Add(duration), nil
+}
+
+func (dec RelativeTime) Placeholder() string {
+	return "now[±DURATION]|" + dec.Absolute.Placeholder()
+}
+
+// Format formats a time as an absolute time.
+func (dec RelativeTime) Format(value time.Time) string {
+	return dec.Absolute.Format(value)
+}
+
+///////////////
+// Utilities //
+
+// parseDuration extends time.ParseDuration with days (d) and weeks (w).
+func parseDuration(source string) (time.Duration, error) {
+	body := strings.TrimLeft(source, "+-")
+	if body == "" || len(source)-len(body) > 1 {
+		return 0, fmt.Errorf("invalid duration %q", source)
+	}
+
+	var res time.Duration
+	for body != "" {
+		// A component is a number followed by a unit.
+		numEnd := strings.IndexFunc(body, isUnit)
+		switch numEnd {
+		case 0:
+			return 0, fmt.Errorf("invalid duration %q", source)
+		case -1:
+			numEnd = len(body)
+		}
+
+		unitEnd := numEnd + strings.IndexFunc(body[numEnd:], isNumeric)
+		if unitEnd < numEnd {
+			unitEnd = len(body)
+		}
+
+		number, unit := body[:numEnd], body[numEnd:unitEnd]
+		body = body[unitEnd:]
+
+		component, err := durationComponent(number, unit)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", source, err)
+		}
+
+		if res > math.MaxInt64-component {
+			return 0, fmt.Errorf("invalid duration %q: overflow", source)
+		}
+
+		res += component
+	}
+
+	if strings.HasPrefix(source, "-") {
+		return -res, nil
+	}
+
+	return res, nil
+}
+
+// durationComponent returns the duration represented by a number and a unit.
+func durationComponent(number, unit string) (time.Duration, error) {
+	days := map[string]float64{"d": 1, "w": 7}[unit]
+	if days == 0 {
+		return time.ParseDuration(number + unit)
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	// Durations are limited to about 290 years, like time.ParseDuration.
+	nanoseconds := value * days * float64(24*time.Hour)
+	if nanoseconds >= math.MaxInt64 {
+		return 0, fmt.Errorf("%s%s overflows a duration", number, unit)
+	}
+
+	return time.Duration(nanoseconds), nil
+}
+
+func isNumeric(r rune) bool { return r == '.' || '0' <= r && r <= '9' }
+func isUnit(r rune) bool    { return !isNumeric(r) }
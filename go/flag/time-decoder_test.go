@@ -0,0 +1,88 @@
+package flag
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected time.Duration
+	}{
+		{"1h30m", 90 * time.Minute},
+		{"-2h", -2 * time.Hour},
+		{"+500ms", 500 * time.Millisecond},
+		{"3d", 72 * time.Hour},
+		{"1w2d", 9 * 24 * time.Hour},
+		{"1.5d12h", 48 * time.Hour},
+		{"0", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			decodeExpect(t, Duration{}, tt.source, tt.expected)
+		})
+	}
+
+	invalid := []string{
+		"", "-", "--3d", "3", "3y", "d", "1.2.3h", "999999999999d", "106752d", "106751d24h",
+	}
+	for _, source := range invalid {
+		t.Run("invalid "+source, func(t *testing.T) {
+			yesErr(t, second(Duration{}.Decode(source)))
+		})
+	}
+}
+
+func TestTime(t *testing.T) {
+	paris := time.FixedZone("Paris", 3600)
+
+	got, err := Time{}.Decode("2024-03-01T10:00:00+01:00")
+	noErr(t, err)
+	eq(t, true, got.Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, paris)))
+	decodeExpect(t, Time{}, "2024-03-01", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	decodeExpect(t, Time{Layouts: []string{time.Kitchen}, Location: paris}, "3:04PM",
+		time.Date(0, 1, 1, 15, 4, 0, 0, paris))
+	yesErr(t, second(Time{}.Decode("01/03/2024")))
+
+	decodeExpect(t, UnixTime{}, "1700000000", time.Unix(1700000000, 0).UTC())
+	decodeExpect(t, UnixTime{Millis: true}, "1700000000123", time.UnixMilli(1700000000123).UTC())
+	yesErr(t, second(UnixTime{}.Decode("1.5")))
+
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	relative := RelativeTime{Now: func() time.Time { return now }}
+	decodeExpect(t, relative, "now", now)
+	decodeExpect(t, relative, "now-2h", now.Add(-2*time.Hour))
+	decodeExpect(t, relative, "-3d", now.Add(-72*time.Hour))
+	decodeExpect(t, relative, "+1w", now.Add(7*24*time.Hour))
+	decodeExpect(t, relative, "2024-03-01", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	yesErr(t, second(relative.Decode("now2h")))
+	yesErr(t, second(relative.Decode("yesterday")))
+}
+
+func TestParser_TimeFlags(t *testing.T) {
+	var (
+		timeout time.Duration
+		since   time.Time
+	)
+
+	par := NewParser()
+	par.Duration("timeout", &timeout, "timeout").Default(time.Minute)
+	RegisterWith(par, RelativeTime{}, "since", &since, "start")
+	par.Time("at", new(time.Time), "instant")
+	noErr(t, par.Parse([]string{"--since", "-1h"}))
+	eq(t, time.Minute, timeout)
+
+	if elapsed := time.Since(since); elapsed < time.Hour || elapsed > time.Hour+time.Minute {
+		t.Errorf("expected about an hour ago, got %v", since)
+	}
+
+	eq(t, `Usage:
+
+Flags:
+  --timeout DURATION                         timeout (default: 1m)
+  --since now[±DURATION]|RFC3339|YYYY-MM-DD  start
+  --at RFC3339|YYYY-MM-DD                    instant
+`, par.Help())
+}
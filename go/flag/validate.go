@@ -0,0 +1,104 @@
+// This file defines ready-made validators, to be given to FluentFlag.Validate.
+
+package flag
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// Range returns a validator ensuring that a value is in the [low, high] interval.
+func Range[T cmp.Ordered](low, high T) func(T) error {
+	return func(value T) error {
+		if value < low || value > high {
+			return fmt.Errorf("%v is not in [%v, %v]", value, low, high)
+		}
+
+		return nil
+	}
+}
+
+// MinLen returns a validator ensuring that a string has at least n bytes.
+func MinLen[S ~string](n int) func(S) error {
+	return func(value S) error { return atLeast(len(value), n) }
+}
+
+// MaxLen returns a validator ensuring that a string has at most n bytes.
+func MaxLen[S ~string](n int) func(S) error {
+	return func(value S) error { return atMost(len(value), n) }
+}
+
+// MinItems returns a validator ensuring that a slice has at least n elements.
+func MinItems[S ~[]E, E any](n int) func(S) error {
+	return func(value S) error { return atLeast(len(value), n) }
+}
+
+// MaxItems returns a validator ensuring that a slice has at most n elements.
+func MaxItems[S ~[]E, E any](n int) func(S) error {
+	return func(value S) error { return atMost(len(value), n) }
+}
+
+// MinKeys returns a validator ensuring that a map has at least n keys.
+func MinKeys[M ~map[K]V, K comparable, V any](n int) func(M) error {
+	return func(value M) error { return atLeast(len(value), n) }
+}
+
+// MaxKeys returns a validator ensuring that a map has at most n keys.
+func MaxKeys[M ~map[K]V, K comparable, V any](n int) func(M) error {
+	return func(value M) error { return atMost(len(value), n) }
+}
+
+// atLeast returns an error if the length is less than n.
+func atLeast(length, n int) error {
+	if length < n {
+		return fmt.Errorf("length %d is less than %d", length, n)
+	}
+
+	return nil
+}
+
+// atMost returns an error if the length is greater than n.
+func atMost(length, n int) error {
+	if length > n {
+		return fmt.Errorf("length %d is greater than %d", length, n)
+	}
+
+	return nil
+}
+
+// Match returns a validator ensuring that a string matches a regular expression.
+func Match(re *regexp.Regexp) func(string) error {
+	return func(value string) error {
+		if !re.MatchString(value) {
+			return fmt.Errorf("%q does not match %s", value, re)
+		}
+
+		return nil
+	}
+}
+
+// OneOf returns a validator ensuring that a value is one of the given values.
+func OneOf[T comparable](values ...T) func(T) error {
+	return func(value T) error {
+		if !slices.Contains(values, value) {
+			return fmt.Errorf("%v is not one of %v", value, values)
+		}
+
+		return nil
+	}
+}
+
+// Each returns a validator applying another validator to every element of a slice.
+func Each[T any](validator func(T) error) func([]T) error {
+	return func(values []T) error {
+		for i, value := range values {
+			if err := validator(value); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,103 @@
+package flag
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func() error
+		valid bool
+	}{
+		{"range inside", func() error { return Range(1, 65535)(8080) }, true},
+		{"range bound", func() error { return Range(1, 65535)(1) }, true},
+		{"range outside", func() error { return Range(1, 65535)(0) }, false},
+		{"min length string", func() error { return MinLen[string](1)("") }, false},
+		{"min length slice", func() error { return MinItems[[]int](1)([]int{1}) }, true},
+		{"max length slice", func() error { return MaxItems[[]int](1)([]int{1, 2}) }, false},
+		{"max length map", func() error { return MaxKeys[map[int]int](1)(map[int]int{}) }, true},
+		{"min length map", func() error { return MinKeys[map[int]int](1)(map[int]int{}) }, false},
+		{"max length string", func() error { return MaxLen[string](2)("abc") }, false},
+		{"match", func() error { return Match(regexp.MustCompile(`^\w+$`))("a_b") }, true},
+		{"no match", func() error { return Match(regexp.MustCompile(`^\w+$`))("a-b") }, false},
+		{"one of", func() error { return OneOf("a", "b")("b") }, true},
+		{"none of", func() error { return OneOf("a", "b")("c") }, false},
+		{"each", func() error { return Each(Range(0, 9))([]int{1, 2, 10}) }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.valid {
+				noErr(t, tt.check())
+			} else {
+				yesErr(t, tt.check())
+			}
+		})
+	}
+}
+
+func TestParser_Validate(t *testing.T) {
+	t.Run("consumed value", func(t *testing.T) {
+		var port int
+		par := NewParser()
+		par.Int("port", &port, "port").Validate(Range(1, 65535))
+		noErr(t, par.Parse([]string{"--port", "80"}))
+		eq(t, 80, port)
+
+		err := par.Parse([]string{"--port", "0"})
+		yesErr(t, err)
+		if err != nil {
+			eq(t, "when consuming port (int singleton): 0 is not in [1, 65535]", err.Error())
+		}
+		eq(t, 80, port)
+	})
+
+	t.Run("default value", func(t *testing.T) {
+		par := NewParser()
+		par.Int("port", new(int), "port").Default(0).Validate(Range(1, 65535))
+		err := par.Parse(nil)
+		yesErr(t, err)
+		if err != nil && !strings.Contains(err.Error(), "default of port") {
+			t.Errorf("error does not mention the flag: %v", err)
+		}
+	})
+
+	t.Run("implicit zero value", func(t *testing.T) {
+		par := NewParser()
+		par.Int("port", new(int), "port").Validate(Range(1, 65535))
+		noErr(t, par.Parse(nil))
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		var ids []int
+		par := NewParser()
+		par.IntSlice("id", &ids, "identifiers").Validate(MaxItems[[]int](2))
+		noErr(t, par.Parse([]string{"--id", "1", "2"}))
+		yesErr(t, par.Parse([]string{"--id", "3"}))
+	})
+
+	t.Run("complete slice", func(t *testing.T) {
+		var ids []int
+		par := NewParser()
+		par.IntSlice("id", &ids, "identifiers").Validate(MinItems[[]int](2))
+		noErr(t, par.Parse([]string{"--id", "1", "--id", "2"}))
+		eq(t, []int{1, 2}, ids)
+
+		ids = nil
+		err := par.Parse([]string{"--id", "1"})
+		yesErr(t, err)
+		if err != nil {
+			eq(t, "when consuming id (slice of int): length 1 is less than 2", err.Error())
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv("TEST_NAME", "")
+		par := NewParser()
+		par.String("name", new(string), "name").Env("TEST_NAME").Validate(MinLen[string](1))
+		yesErr(t, par.Parse(nil))
+	})
+}